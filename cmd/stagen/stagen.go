@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"path/filepath"
 
 	"github.com/pixality-inc/golang-core/logger"
 	"github.com/spf13/cobra"
@@ -49,79 +50,156 @@ func runCommand(rootCtx context.Context, cliTool cli.Cli) {
 
 	// Build
 
-	rootCmd.AddCommand(&cobra.Command{
-		Use:   "build [dir]",
-		Short: "Build project in directory [dir]",
-		Args:  cobra.MaximumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) { //nolint:contextcheck
-			workDir := config.RootDir()
+	{
+		cmd := &cobra.Command{
+			Use:   "build [dir]",
+			Short: "Build project in directory [dir]",
+			Args:  cobra.MaximumNArgs(1),
+			Run: func(cmd *cobra.Command, args []string) { //nolint:contextcheck
+				workDir := config.RootDir()
 
-			if len(args) > 0 {
-				workDir = args[0]
-			}
+				if len(args) > 0 {
+					workDir = args[0]
+				}
 
-			if err := cliTool.Build(cmd.Context(), workDir); err != nil {
-				log.WithError(err).Fatal()
-			}
-		},
-	})
+				opts, err := cliOptions(cmd)
+				if err != nil {
+					log.WithError(err).Fatal()
+				}
+
+				if err = cliTool.Build(cmd.Context(), workDir, opts...); err != nil {
+					log.WithError(err).Fatal()
+				}
+			},
+		}
+
+		addConfigFlags(cmd)
+		addBuildFlags(cmd)
+
+		rootCmd.AddCommand(cmd)
+	}
+
+	// Check
+
+	{
+		cmd := &cobra.Command{
+			Use:   "check [dir]",
+			Short: "Check project in directory [dir] without building it",
+			Args:  cobra.MaximumNArgs(1),
+			Run: func(cmd *cobra.Command, args []string) { //nolint:contextcheck
+				workDir := config.RootDir()
+
+				if len(args) > 0 {
+					workDir = args[0]
+				}
+
+				opts, err := cliOptions(cmd)
+				if err != nil {
+					log.WithError(err).Fatal()
+				}
+
+				if err = cliTool.Check(cmd.Context(), workDir, opts...); err != nil {
+					log.WithError(err).Fatal()
+				}
+			},
+		}
+
+		addConfigFlags(cmd)
+
+		rootCmd.AddCommand(cmd)
+	}
 
 	// Watch
 
-	rootCmd.AddCommand(&cobra.Command{
-		Use:   "watch [dir]",
-		Short: "Watch project and rebuild in directory [dir]",
-		Args:  cobra.MaximumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) { //nolint:contextcheck
-			workDir := config.RootDir()
+	{
+		cmd := &cobra.Command{
+			Use:   "watch [dir]",
+			Short: "Watch project and rebuild in directory [dir]",
+			Args:  cobra.MaximumNArgs(1),
+			Run: func(cmd *cobra.Command, args []string) { //nolint:contextcheck
+				workDir := config.RootDir()
 
-			if len(args) > 0 {
-				workDir = args[0]
-			}
+				if len(args) > 0 {
+					workDir = args[0]
+				}
 
-			if err := cliTool.Watch(cmd.Context(), workDir); err != nil {
-				log.WithError(err).Fatal()
-			}
-		},
-	})
+				opts, err := cliOptions(cmd)
+				if err != nil {
+					log.WithError(err).Fatal()
+				}
+
+				if err = cliTool.Watch(cmd.Context(), workDir, opts...); err != nil {
+					log.WithError(err).Fatal()
+				}
+			},
+		}
+
+		addConfigFlags(cmd)
+		addBuildFlags(cmd)
+
+		rootCmd.AddCommand(cmd)
+	}
 
 	// Web
 
-	rootCmd.AddCommand(&cobra.Command{
-		Use:   "web [dir]",
-		Short: "Serve project over http in directory [dir]",
-		Args:  cobra.MaximumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) { //nolint:contextcheck
-			workDir := config.RootDir()
+	{
+		cmd := &cobra.Command{
+			Use:   "web [dir]",
+			Short: "Serve project over http in directory [dir]",
+			Args:  cobra.MaximumNArgs(1),
+			Run: func(cmd *cobra.Command, args []string) { //nolint:contextcheck
+				workDir := config.RootDir()
 
-			if len(args) > 0 {
-				workDir = args[0]
-			}
+				if len(args) > 0 {
+					workDir = args[0]
+				}
 
-			if err := cliTool.Web(cmd.Context(), workDir); err != nil {
-				log.WithError(err).Fatal()
-			}
-		},
-	})
+				opts, err := cliOptions(cmd)
+				if err != nil {
+					log.WithError(err).Fatal()
+				}
+
+				if err = cliTool.Web(cmd.Context(), workDir, opts...); err != nil {
+					log.WithError(err).Fatal()
+				}
+			},
+		}
+
+		addConfigFlags(cmd)
+
+		rootCmd.AddCommand(cmd)
+	}
 
 	// Dev
 
-	rootCmd.AddCommand(&cobra.Command{
-		Use:   "dev [dir]",
-		Short: "Serve project over http and watch for changes in directory [dir]",
-		Args:  cobra.MaximumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) { //nolint:contextcheck
-			workDir := config.RootDir()
+	{
+		cmd := &cobra.Command{
+			Use:   "dev [dir]",
+			Short: "Serve project over http and watch for changes in directory [dir]",
+			Args:  cobra.MaximumNArgs(1),
+			Run: func(cmd *cobra.Command, args []string) { //nolint:contextcheck
+				workDir := config.RootDir()
 
-			if len(args) > 0 {
-				workDir = args[0]
-			}
+				if len(args) > 0 {
+					workDir = args[0]
+				}
 
-			if err := cliTool.Dev(cmd.Context(), workDir); err != nil {
-				log.WithError(err).Fatal()
-			}
-		},
-	})
+				opts, err := cliOptions(cmd)
+				if err != nil {
+					log.WithError(err).Fatal()
+				}
+
+				if err = cliTool.Dev(cmd.Context(), workDir, opts...); err != nil {
+					log.WithError(err).Fatal()
+				}
+			},
+		}
+
+		addConfigFlags(cmd)
+		addBuildFlags(cmd)
+
+		rootCmd.AddCommand(cmd)
+	}
 
 	// Execute
 
@@ -130,6 +208,118 @@ func runCommand(rootCtx context.Context, cliTool cli.Cli) {
 	}
 }
 
+func addConfigFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("config", "c", "", "config file (default [dir]/config.yaml)")
+	cmd.Flags().StringP("output", "o", "", "output directory (default [dir]/build)")
+	cmd.Flags().StringP("env", "e", "", "environment")
+	cmd.Flags().String("base-url", "", "site base url")
+}
+
+func addBuildFlags(cmd *cobra.Command) {
+	cmd.Flags().IntP("jobs", "j", 0, "number of pages rendered in parallel (default GOMAXPROCS)")
+	cmd.Flags().Bool("drafts", false, "build draft pages")
+	cmd.Flags().Bool("future", false, "build pages with publish date in the future")
+	cmd.Flags().String("report", "", "write a json build report to the file")
+	cmd.Flags().Bool("strict", false, "fail the build on warnings")
+}
+
+func cliOptions(cmd *cobra.Command) ([]cli.Option, error) {
+	opts := make([]cli.Option, 0)
+
+	if cmd.Flags().Changed("config") {
+		configFile, err := cmd.Flags().GetString("config")
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, cli.WithConfigFile(configFile))
+	}
+
+	if cmd.Flags().Changed("output") {
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return nil, err
+		}
+
+		output, err = filepath.Abs(output)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, cli.WithOutput(output))
+	}
+
+	if cmd.Flags().Changed("env") {
+		env, err := cmd.Flags().GetString("env")
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, cli.WithEnv(env))
+	}
+
+	if cmd.Flags().Changed("base-url") {
+		baseUrl, err := cmd.Flags().GetString("base-url")
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, cli.WithBaseUrl(baseUrl))
+	}
+
+	if cmd.Flags().Changed("jobs") {
+		jobs, err := cmd.Flags().GetInt("jobs")
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, cli.WithJobs(jobs))
+	}
+
+	if cmd.Flags().Changed("drafts") {
+		drafts, err := cmd.Flags().GetBool("drafts")
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, cli.WithDrafts(drafts))
+	}
+
+	if cmd.Flags().Changed("future") {
+		future, err := cmd.Flags().GetBool("future")
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, cli.WithFuture(future))
+	}
+
+	if cmd.Flags().Changed("report") {
+		report, err := cmd.Flags().GetString("report")
+		if err != nil {
+			return nil, err
+		}
+
+		report, err = filepath.Abs(report)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, cli.WithReport(report))
+	}
+
+	if cmd.Flags().Changed("strict") {
+		strict, err := cmd.Flags().GetBool("strict")
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, cli.WithStrict(strict))
+	}
+
+	return opts, nil
+}
+
 func main() {
 	wire := wiring.New()
 	defer wire.Shutdown()
@@ -2,8 +2,8 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"path/filepath"
 	"sync"
 
 	"github.com/pixality-inc/golang-core/clock"
@@ -13,15 +13,19 @@ import (
 
 	"github.com/stagens/stagen/internal/config"
 	"github.com/stagens/stagen/pkg/git"
+	"github.com/stagens/stagen/pkg/source_error"
 	"github.com/stagens/stagen/pkg/stagen"
 )
 
+var ErrCheckFailed = errors.New("check failed")
+
 type Cli interface {
 	Init(ctx context.Context, workDir string, name string, withGit bool) error
-	Build(ctx context.Context, workDir string) error
-	Watch(ctx context.Context, workDir string) error
-	Web(ctx context.Context, workDir string) error
-	Dev(ctx context.Context, workDir string) error
+	Build(ctx context.Context, workDir string, opts ...Option) error
+	Check(ctx context.Context, workDir string, opts ...Option) error
+	Watch(ctx context.Context, workDir string, opts ...Option) error
+	Web(ctx context.Context, workDir string, opts ...Option) error
+	Dev(ctx context.Context, workDir string, opts ...Option) error
 }
 
 type Impl struct {
@@ -53,21 +57,47 @@ func (c *Impl) Init(ctx context.Context, workDir string, name string, withGit bo
 	return nil
 }
 
-func (c *Impl) Build(ctx context.Context, workDir string) error {
-	stagenTool, err := c.init(ctx, workDir, nil)
+func (c *Impl) Build(ctx context.Context, workDir string, opts ...Option) error {
+	stagenTool, err := c.init(ctx, workDir, nil, opts...)
 	if err != nil {
 		return err
 	}
 
 	if err = stagenTool.Build(ctx); err != nil {
+		c.logSourceExcerpt(ctx, err)
+
 		return err
 	}
 
 	return nil
 }
 
-func (c *Impl) Watch(ctx context.Context, workDir string) error {
-	stagenTool, err := c.init(ctx, workDir, nil)
+func (c *Impl) Check(ctx context.Context, workDir string, opts ...Option) error {
+	stagenTool, err := c.init(ctx, workDir, nil, opts...)
+	if err != nil {
+		return err
+	}
+
+	problems, err := stagenTool.Check(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	log := c.log.GetLogger(ctx)
+
+	for _, problem := range problems {
+		log.Error(problem.String())
+	}
+
+	return fmt.Errorf("%w: %d problem(s) found", ErrCheckFailed, len(problems))
+}
+
+func (c *Impl) Watch(ctx context.Context, workDir string, opts ...Option) error {
+	stagenTool, err := c.init(ctx, workDir, nil, previewOptions(opts)...)
 	if err != nil {
 		return err
 	}
@@ -79,8 +109,8 @@ func (c *Impl) Watch(ctx context.Context, workDir string) error {
 	return nil
 }
 
-func (c *Impl) Web(ctx context.Context, workDir string) error {
-	stagenTool, err := c.init(ctx, workDir, nil)
+func (c *Impl) Web(ctx context.Context, workDir string, opts ...Option) error {
+	stagenTool, err := c.init(ctx, workDir, nil, opts...)
 	if err != nil {
 		return err
 	}
@@ -92,8 +122,8 @@ func (c *Impl) Web(ctx context.Context, workDir string) error {
 	return nil
 }
 
-func (c *Impl) Dev(ctx context.Context, workDir string) error {
-	stagenTool, err := c.init(ctx, workDir, nil)
+func (c *Impl) Dev(ctx context.Context, workDir string, opts ...Option) error {
+	stagenTool, err := c.init(ctx, workDir, nil, previewOptions(opts)...)
 	if err != nil {
 		return err
 	}
@@ -101,6 +131,8 @@ func (c *Impl) Dev(ctx context.Context, workDir string) error {
 	log := c.log.GetLogger(ctx)
 
 	if err = stagenTool.Build(ctx); err != nil {
+		c.logSourceExcerpt(ctx, err)
+
 		return fmt.Errorf("build failed: %w", err)
 	}
 
@@ -123,24 +155,47 @@ func (c *Impl) Dev(ctx context.Context, workDir string) error {
 	return nil
 }
 
-func (c *Impl) init(_ context.Context, workDir string, cfg *config.Config) (stagen.Stagen, error) {
+// previewOptions builds the drafts in watch and dev, an explicit --drafts flag still wins
+func previewOptions(opts []Option) []Option {
+	return append([]Option{WithDrafts(true)}, opts...)
+}
+
+func (c *Impl) logSourceExcerpt(ctx context.Context, err error) {
+	if excerpt := source_error.ExcerptOf(err); excerpt != "" {
+		c.log.GetLogger(ctx).Error("\n" + excerpt)
+	}
+}
+
+func (c *Impl) init(_ context.Context, workDir string, cfg *config.Config, opts ...Option) (stagen.Stagen, error) {
+	initOptions := newOptions(opts)
+
+	configFilename := initOptions.configFile
+	if configFilename == "" {
+		configFilename = config.ConfigFile(workDir)
+	}
+
 	if cfg == nil {
 		var err error
 
-		configFilename := filepath.Join(workDir, "config.yaml")
-
 		cfg, err = config.NewConfigFromFile(configFilename)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	initOptions.apply(cfg)
+
 	localStorage := storage.NewLocalStorage(
 		providers.NewOsProvider(workDir),
 		providers.NoUrlProviderImpl,
 	)
 
-	stagenTool := stagen.New(&cfg.Stagen, &cfg.Site, c.clock, c.git, localStorage, workDir)
+	stagenOptions, err := sourceDateEpochOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	stagenTool := stagen.New(&cfg.Stagen, &cfg.Site, c.clock, c.git, localStorage, workDir, configFilename, stagenOptions...)
 
 	return stagenTool, nil
 }
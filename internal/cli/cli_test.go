@@ -2,12 +2,18 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/fs"
+	"maps"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -15,6 +21,8 @@ import (
 
 	"github.com/stagens/stagen/internal/config"
 	"github.com/stagens/stagen/pkg/git"
+	"github.com/stagens/stagen/pkg/source_error"
+	"github.com/stagens/stagen/pkg/stagen"
 )
 
 type fakeClock struct {
@@ -91,6 +99,7 @@ func TestBuild(t *testing.T) {
 	tests := []struct {
 		name    string
 		testDir string
+		opts    []Option
 	}{
 		{
 			name:    "base",
@@ -112,60 +121,1007 @@ func TestBuild(t *testing.T) {
 			name:    "generators",
 			testDir: filepath.Join(rootDir(), "tests/05-generators"),
 		},
+		{
+			name:    "times",
+			testDir: filepath.Join(rootDir(), "tests/06-times"),
+		},
+		{
+			name:    "drafts",
+			testDir: filepath.Join(rootDir(), "tests/07-drafts"),
+		},
+		{
+			name:    "schedule",
+			testDir: filepath.Join(rootDir(), "tests/08-schedule"),
+		},
+		{
+			name:    "overrides",
+			testDir: filepath.Join(rootDir(), "tests/09-overrides"),
+			opts: []Option{
+				WithEnv("production"),
+				WithBaseUrl("https://example.com"),
+			},
+		},
+		{
+			name:    "permalinks",
+			testDir: filepath.Join(rootDir(), "tests/11-permalinks"),
+		},
+		{
+			name:    "aliases",
+			testDir: filepath.Join(rootDir(), "tests/12-aliases"),
+			opts: []Option{
+				WithBaseUrl("https://example.com"),
+			},
+		},
+		{
+			name:    "pretty_urls",
+			testDir: filepath.Join(rootDir(), "tests/13-pretty-urls"),
+		},
+		{
+			name:    "pretty_urls_no_trailing_slash",
+			testDir: filepath.Join(rootDir(), "tests/14-pretty-urls-no-trailing-slash"),
+		},
+		{
+			name:    "page_bundles",
+			testDir: filepath.Join(rootDir(), "tests/15-page-bundles"),
+		},
+		{
+			name:    "passthrough",
+			testDir: filepath.Join(rootDir(), "tests/16-passthrough"),
+		},
+		{
+			name:    "page_summaries",
+			testDir: filepath.Join(rootDir(), "tests/17-page-summaries"),
+		},
+		{
+			name:    "toc",
+			testDir: filepath.Join(rootDir(), "tests/18-toc"),
+		},
+		{
+			name:    "toc_levels",
+			testDir: filepath.Join(rootDir(), "tests/19-toc-levels"),
+		},
+		{
+			name:    "sections",
+			testDir: filepath.Join(rootDir(), "tests/20-sections"),
+		},
+		{
+			name:    "page_navigation",
+			testDir: filepath.Join(rootDir(), "tests/21-page-navigation"),
+		},
+		{
+			name:    "pagination",
+			testDir: filepath.Join(rootDir(), "tests/22-pagination"),
+		},
+		{
+			name:    "void_tags",
+			testDir: filepath.Join(rootDir(), "tests/23-void-tags"),
+		},
+		{
+			name:    "i18n",
+			testDir: filepath.Join(rootDir(), "tests/24-i18n"),
+		},
+		{
+			name:    "i18n_default_prefix",
+			testDir: filepath.Join(rootDir(), "tests/25-i18n-default-prefix"),
+		},
+		{
+			name:    "toc_html",
+			testDir: filepath.Join(rootDir(), "tests/26-toc-html"),
+		},
 		// @todo includes
 		// @todo extras
 		// @todo theme changing
 		// @todo layout changing
 	}
 
-	for _, testCase := range tests {
-		t.Run(testCase.name, func(t *testing.T) {
-			t.Parallel()
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			workDir := testCase.testDir
+
+			buildDir := filepath.Join(workDir, "build")
+			stagenDir := filepath.Join(workDir, ".stagen")
+			checkDir := filepath.Join(workDir, "_check")
+
+			removeBuild(t, workDir)
+
+			err := os.RemoveAll(stagenDir)
+			require.NoError(t, err)
+
+			t.Cleanup(func() {
+				if !t.Failed() {
+					removeBuild(t, workDir)
+
+					err = os.RemoveAll(stagenDir)
+					require.NoError(t, err)
+				}
+			})
+
+			gitTool := git.New("git")
+
+			cliTool := New(clocks, gitTool)
+
+			err = cliTool.Build(ctx, workDir, testCase.opts...)
+			require.NoError(t, err)
+
+			diffs, err := DiffDirs(buildDir, checkDir)
+			require.NoError(t, err)
+
+			if len(diffs) > 0 {
+				t.Log(strings.Join(diffs, "\n"))
+			}
+
+			require.Empty(t, diffs)
+		})
+	}
+}
+
+func TestBuildRemovesStaleFiles(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	clocks := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	workDir := t.TempDir()
+
+	err := os.CopyFS(workDir, os.DirFS(filepath.Join(rootDir(), "tests/01-base")))
+	require.NoError(t, err)
+
+	err = os.RemoveAll(filepath.Join(workDir, "build"))
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(workDir, "pages/about.md"), []byte("About"), 0o600)
+	require.NoError(t, err)
+
+	cliTool := New(clocks, git.New("git"))
+
+	err = cliTool.Build(ctx, workDir)
+	require.NoError(t, err)
+
+	buildDir := filepath.Join(workDir, "build")
+
+	unchangedFilename := filepath.Join(buildDir, "index.html")
+
+	unchangedTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	err = os.Chtimes(unchangedFilename, unchangedTime, unchangedTime)
+	require.NoError(t, err)
+
+	err = os.Rename(filepath.Join(workDir, "pages/about.md"), filepath.Join(workDir, "pages/about-us.md"))
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(buildDir, "manual.txt"), []byte("manual"), 0o600)
+	require.NoError(t, err)
+
+	err = cliTool.Build(ctx, workDir)
+	require.NoError(t, err)
+
+	require.NoFileExists(t, filepath.Join(buildDir, "about.html"))
+	require.FileExists(t, filepath.Join(buildDir, "about-us.html"))
+	require.FileExists(t, filepath.Join(buildDir, "manual.txt"))
+
+	unchangedStat, err := os.Stat(unchangedFilename)
+	require.NoError(t, err)
+	require.True(t, unchangedStat.ModTime().Equal(unchangedTime))
+}
+
+func TestBuildFsPageTimes(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	clocks := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	workDir := t.TempDir()
+
+	err := os.CopyFS(workDir, os.DirFS(filepath.Join(rootDir(), "tests/01-base")))
+	require.NoError(t, err)
+
+	configFile, err := os.OpenFile(filepath.Join(workDir, "config.yaml"), os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+
+	_, err = configFile.WriteString("stagen:\n  settings:\n    page_time_source: fs\n")
+	require.NoError(t, err)
+	require.NoError(t, configFile.Close())
+
+	pageTimes := `{{ .Page.CreatedAt.Unix }}|{{ .Page.ModifiedAt.UTC.Format "2006-01-02T15:04:05Z07:00" }}`
+
+	files := map[string]string{
+		"pages/plain.html":   pageTimes,
+		"pages/dated.html":   "---\ndate: 2024-03-01\n---\n" + pageTimes,
+		"pages/lastmod.html": "---\ndate: 2024-03-01\nlastmod: 2024-04-15T10:30:00Z\n---\n" + pageTimes,
+	}
+
+	modTime := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	startTime := time.Now().Add(-time.Second)
+
+	for filename, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(workDir, filename), []byte(content), 0o600))
+		require.NoError(t, os.Chtimes(filepath.Join(workDir, filename), modTime, modTime))
+	}
+
+	cliTool := New(clocks, git.New("git"))
+
+	err = cliTool.Build(ctx, workDir)
+	require.NoError(t, err)
+
+	readPage := func(filename string) (int64, string) {
+		content, err := os.ReadFile(filepath.Join(workDir, "build", filename))
+		require.NoError(t, err)
+
+		createdAt, modifiedAt, ok := strings.Cut(
+			strings.TrimSpace(strings.NewReplacer("[DEFAULT LAYOUT]", "", "[/DEFAULT LAYOUT]", "").Replace(string(content))),
+			"|",
+		)
+		require.True(t, ok)
+
+		createdAtUnix, err := strconv.ParseInt(createdAt, 10, 64)
+		require.NoError(t, err)
+
+		return createdAtUnix, modifiedAt
+	}
+
+	// the birth time, or the change time where there is none, comes from the test itself
+	createdAt, modifiedAt := readPage("plain.html")
+	require.GreaterOrEqual(t, createdAt, startTime.Unix())
+	require.LessOrEqual(t, createdAt, time.Now().Unix())
+	require.Equal(t, "2020-06-01T12:00:00Z", modifiedAt)
+
+	// with the fs source the date does not override the modification time
+	createdAt, modifiedAt = readPage("dated.html")
+	require.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).Unix(), createdAt)
+	require.Equal(t, "2020-06-01T12:00:00Z", modifiedAt)
+
+	createdAt, modifiedAt = readPage("lastmod.html")
+	require.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).Unix(), createdAt)
+	require.Equal(t, "2024-04-15T10:30:00Z", modifiedAt)
+}
+
+type countingGit struct {
+	git.Git
+
+	logCalls atomic.Int32
+}
+
+func (g *countingGit) Log(ctx context.Context, workDir string, paths ...string) ([]git.Commit, error) {
+	g.logCalls.Add(1)
+
+	return g.Git.Log(ctx, workDir, paths...)
+}
+
+func TestBuildGitInfo(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	clocks := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	gitTool := &countingGit{Git: git.New("git")}
+
+	if !gitTool.HasGit(ctx) {
+		t.Skip("git is not installed")
+	}
+
+	// the project is a subdirectory of the repository, paths are matched relative to it
+	repoDir := t.TempDir()
+	workDir := filepath.Join(repoDir, "site")
+
+	err := os.CopyFS(workDir, os.DirFS(filepath.Join(rootDir(), "tests/01-base")))
+	require.NoError(t, err)
+
+	gitInfo := `{{ with .Page.Git }}{{ .CreatedAt.UTC.Format "2006-01-02" }}|{{ .ModifiedAt.UTC.Format "2006-01-02" }}|` +
+		`{{ range .Authors }}{{ .Name }}/{{ .Email }};{{ end }}|{{ .Hash }}|{{ .Subject }}{{ end }}`
+
+	commit := func(name string, email string, date string, message string, files map[string]string) string {
+		for filename, content := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(workDir, filename), []byte(content), 0o600))
+		}
+
+		env := append(
+			os.Environ(),
+			"GIT_CONFIG_GLOBAL=/dev/null",
+			"GIT_CONFIG_NOSYSTEM=1",
+			"GIT_AUTHOR_NAME="+name,
+			"GIT_AUTHOR_EMAIL="+email,
+			"GIT_AUTHOR_DATE="+date,
+			"GIT_COMMITTER_NAME="+name,
+			"GIT_COMMITTER_EMAIL="+email,
+			"GIT_COMMITTER_DATE="+date,
+		)
+
+		for _, args := range [][]string{{"add", "."}, {"commit", "-q", "-m", message}} {
+			cmd := exec.CommandContext(ctx, "git", args...)
+			cmd.Dir = repoDir
+			cmd.Env = env
+
+			output, err := cmd.CombinedOutput()
+			require.NoError(t, err, string(output))
+		}
+
+		cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+		cmd.Dir = repoDir
+
+		output, err := cmd.Output()
+		require.NoError(t, err)
+
+		return strings.TrimSpace(string(output))
+	}
+
+	require.NoError(t, gitTool.Init(ctx, repoDir))
+
+	commit("Alice", "alice@example.com", "2024-01-02T10:00:00Z", "Add first", map[string]string{
+		"pages/first.html": gitInfo,
+	})
+
+	secondHash := commit("Bob", "bob@example.com", "2024-02-03T10:00:00Z", "Edit first, add second", map[string]string{
+		"pages/first.html":  gitInfo + "\n<!-- edited -->",
+		"pages/second.html": gitInfo,
+	})
+
+	thirdHash := commit("Alice", "alice@example.com", "2024-03-04T10:00:00Z", "Edit first again", map[string]string{
+		"pages/first.html": gitInfo + "\n<!-- edited again -->",
+	})
+
+	// not committed yet
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "pages/draft.html"), []byte(gitInfo), 0o600))
+
+	readPage := func(buildDir string, filename string) string {
+		content, err := os.ReadFile(filepath.Join(buildDir, filename))
+		require.NoError(t, err)
+
+		page := strings.NewReplacer("[DEFAULT LAYOUT]", "", "[/DEFAULT LAYOUT]", "").Replace(string(content))
+		page, _, _ = strings.Cut(page, "<!--")
+
+		return strings.TrimSpace(page)
+	}
+
+	cliTool := New(clocks, gitTool)
+
+	err = cliTool.Build(ctx, workDir)
+	require.NoError(t, err)
+
+	// the whole history is read by a single git log
+	require.Equal(t, int32(1), gitTool.logCalls.Load())
+
+	buildDir := filepath.Join(workDir, "build")
+
+	require.Equal(
+		t,
+		"2024-01-02|2024-03-04|Alice/alice@example.com;Bob/bob@example.com;|"+thirdHash+"|Edit first again",
+		readPage(buildDir, "first.html"),
+	)
+	require.Equal(
+		t,
+		"2024-02-03|2024-02-03|Bob/bob@example.com;|"+secondHash+"|Edit first, add second",
+		readPage(buildDir, "second.html"),
+	)
+	require.Equal(t, "0001-01-01|0001-01-01|||", readPage(buildDir, "draft.html"))
+
+	// outside of a git checkout the info is empty and git log is not run
+	outsideDir := t.TempDir()
+
+	err = os.CopyFS(outsideDir, os.DirFS(workDir))
+	require.NoError(t, err)
+
+	err = os.RemoveAll(filepath.Join(outsideDir, "build"))
+	require.NoError(t, err)
+
+	err = cliTool.Build(ctx, outsideDir, WithStrict(true))
+	require.NoError(t, err)
+	require.Equal(t, int32(1), gitTool.logCalls.Load())
+	require.Equal(t, "0001-01-01|0001-01-01|||", readPage(filepath.Join(outsideDir, "build"), "first.html"))
+}
+
+func TestBuildDraftsByCommand(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	clocks := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	workDir := t.TempDir()
+
+	err := os.CopyFS(workDir, os.DirFS(filepath.Join(rootDir(), "tests/07-drafts")))
+	require.NoError(t, err)
+
+	err = os.RemoveAll(filepath.Join(workDir, "build"))
+	require.NoError(t, err)
+
+	buildDir := filepath.Join(workDir, "build")
+
+	cliTool := New(clocks, git.New("git"))
+
+	// dev is the default env, the build command still leaves the drafts out
+	err = cliTool.Build(ctx, workDir, WithEnv("dev"))
+	require.NoError(t, err)
+
+	require.FileExists(t, filepath.Join(buildDir, "published.html"))
+	require.NoFileExists(t, filepath.Join(buildDir, "draft.html"))
+
+	err = cliTool.Build(ctx, workDir, WithEnv("dev"), WithDrafts(true))
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(buildDir, "draft.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "[DRAFT]")
+
+	// watch and dev build the drafts unless --drafts=false is passed
+	previewConfig := config.NewConfig()
+
+	newOptions(previewOptions(nil)).apply(previewConfig)
+	require.True(t, previewConfig.Stagen.SettingsValue.BuildDraftsValue)
+
+	newOptions(previewOptions([]Option{WithDrafts(false)})).apply(previewConfig)
+	require.False(t, previewConfig.Stagen.SettingsValue.BuildDraftsValue)
+}
+
+// removeBuild removes the build directory with the builds it links to
+func removeBuild(t *testing.T, workDir string) {
+	t.Helper()
+
+	buildVersions, err := filepath.Glob(filepath.Join(workDir, ".build.*"))
+	require.NoError(t, err)
+
+	for _, dir := range append(buildVersions, filepath.Join(workDir, "build")) {
+		require.NoError(t, os.RemoveAll(dir))
+	}
+}
+
+// requireBuildLink checks the build directory links to the last build and no other build is left next to it
+func requireBuildLink(t *testing.T, workDir string) {
+	t.Helper()
+
+	target, err := os.Readlink(filepath.Join(workDir, "build"))
+	require.NoError(t, err)
+
+	siblings, err := filepath.Glob(filepath.Join(workDir, ".build.*"))
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(workDir, target)}, siblings)
+}
+
+func TestBuildSwapsBuildLink(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	clocks := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	workDir := t.TempDir()
+
+	err := os.CopyFS(workDir, os.DirFS(filepath.Join(rootDir(), "tests/01-base")))
+	require.NoError(t, err)
+
+	buildDir := filepath.Join(workDir, "build")
+
+	// a build directory of an older version is a directory, its foreign files are kept
+	err = os.RemoveAll(buildDir)
+	require.NoError(t, err)
+
+	err = os.MkdirAll(buildDir, 0o755)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(buildDir, "manual.txt"), []byte("manual"), 0o600)
+	require.NoError(t, err)
+
+	cliTool := New(clocks, git.New("git"))
+
+	targets := make([]string, 0)
+
+	for range 3 {
+		err = cliTool.Build(ctx, workDir)
+		require.NoError(t, err)
+
+		requireBuildLink(t, workDir)
+
+		require.FileExists(t, filepath.Join(buildDir, "index.html"))
+		require.FileExists(t, filepath.Join(buildDir, "manual.txt"))
+
+		target, err := os.Readlink(buildDir)
+		require.NoError(t, err)
+
+		targets = append(targets, target)
+	}
+
+	require.Equal(t, []string{".build.a", ".build.b", ".build.a"}, targets)
+}
+
+func TestBuildDirParentNotWritable(t *testing.T) {
+	t.Parallel()
+
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to any directory")
+	}
+
+	ctx := context.Background()
+
+	clocks := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	workDir := t.TempDir()
+
+	err := os.CopyFS(workDir, os.DirFS(filepath.Join(rootDir(), "tests/01-base")))
+	require.NoError(t, err)
+
+	configFile, err := os.OpenFile(filepath.Join(workDir, "config.yaml"), os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+
+	_, err = configFile.WriteString("stagen:\n  settings:\n    build_dir: output/build\n")
+	require.NoError(t, err)
+	require.NoError(t, configFile.Close())
+
+	outputDir := filepath.Join(workDir, "output")
+
+	err = os.MkdirAll(filepath.Join(outputDir, "build"), 0o755)
+	require.NoError(t, err)
+
+	err = os.Chmod(outputDir, 0o555)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = os.Chmod(outputDir, 0o755) //nolint:errcheck
+	})
+
+	cliTool := New(clocks, git.New("git"))
+
+	err = cliTool.Build(ctx, workDir)
+	require.ErrorIs(t, err, stagen.ErrBuildDirParentNotWritable)
+}
+
+func TestBuildKeepsPreviousOutputOnFailure(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	clocks := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	workDir := t.TempDir()
+
+	err := os.CopyFS(workDir, os.DirFS(filepath.Join(rootDir(), "tests/01-base")))
+	require.NoError(t, err)
+
+	buildDir := filepath.Join(workDir, "build")
+
+	err = os.RemoveAll(buildDir)
+	require.NoError(t, err)
+
+	brokenFilename := filepath.Join(workDir, "pages/broken.html")
+
+	err = os.WriteFile(brokenFilename, []byte(`{{ template "missing" }}`), 0o600)
+	require.NoError(t, err)
+
+	cliTool := New(clocks, git.New("git"))
+
+	// a failed first build leaves no empty build dir behind
+	err = cliTool.Build(ctx, workDir)
+	require.Error(t, err)
+	require.NoDirExists(t, buildDir)
+
+	err = os.Remove(brokenFilename)
+	require.NoError(t, err)
+
+	err = cliTool.Build(ctx, workDir)
+	require.NoError(t, err)
+
+	err = os.WriteFile(brokenFilename, []byte(`{{ template "missing" }}`), 0o600)
+	require.NoError(t, err)
+
+	err = cliTool.Build(ctx, workDir)
+	require.Error(t, err)
+
+	require.FileExists(t, filepath.Join(buildDir, "index.html"))
+	require.NoFileExists(t, filepath.Join(buildDir, "broken.html"))
+	requireBuildLink(t, workDir)
+}
+
+func TestRebuild(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	clocks := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	files := map[string]string{
+		"pages/about.md":                         "About",
+		"pages/other.md":                         "---\nlayout: other\n---\nOther",
+		"pages/with-import.md":                   "---\nimports:\n  imports:\n    - name: example\n---\n<Example />",
+		"pages/colors.md":                        "{{ range .Databases.colors.Data }}{{ .id }};{{ end }}",
+		"pages/dynamic.md":                       `{{ $key := printf "%s%s" "Data" "bases" }}{{ range (index . $key).colors.Data }}{{ .id }};{{ end }}`,
+		"pages/alias.md":                         `{{ $site := . }}{{ range $key, $value := $site }}{{ if eq $key "Databases" }}{{ range $value.colors.Data }}{{ .id }};{{ end }}{{ end }}{{ end }}`,
+		"pages/partial.md":                       `{{ include "colors" (dict "site" .) }}`,
+		"themes/default/layouts/other.html.tmpl": `{{- define "other" }}[OTHER v1] {{ page_content }}{{ end -}}`,
+		"templates/imports/example.html.tmpl":    `{{- define "macro:Example" }}[Example v1]{{ end -}}`,
+		"templates/includes/colors.html.tmpl":    `{{- define "colors" }}{{ with .site }}{{ range .Databases.colors.Data }}{{ .id }};{{ end }}{{ end }}{{ end -}}`,
+		"databases/colors.yaml":                  "name: colors\ndata:\n  - id: red\n",
+		"public/robots.txt":                      "robots v1",
+	}
+
+	tests := []struct {
+		name     string
+		changes  map[string]string
+		rendered []string
+		contains map[string]string
+	}{
+		{
+			name:    "page",
+			changes: map[string]string{"pages/about.md": "About v2"},
+			// the dynamic pages read the data by a computed key or range over it, so they follow every data change
+			rendered: []string{"about.html", "alias.html", "dynamic.html"},
+			contains: map[string]string{"about.html": "About v2", "robots.txt": "robots v1"},
+		},
+		{
+			name:     "layout",
+			changes:  map[string]string{"themes/default/layouts/other.html.tmpl": `{{- define "other" }}[OTHER v2] {{ page_content }}{{ end -}}`},
+			rendered: []string{"other.html"},
+			contains: map[string]string{"other.html": "[OTHER v2]", "robots.txt": "robots v1"},
+		},
+		{
+			name:     "import",
+			changes:  map[string]string{"templates/imports/example.html.tmpl": `{{- define "macro:Example" }}[Example v2]{{ end -}}`},
+			rendered: []string{"with-import.html"},
+			contains: map[string]string{"with-import.html": "[Example v2]", "robots.txt": "robots v1"},
+		},
+		{
+			name:     "database",
+			changes:  map[string]string{"databases/colors.yaml": "name: colors\ndata:\n  - id: red\n  - id: blue\n"},
+			rendered: []string{"alias.html", "colors.html", "dynamic.html", "partial.html"},
+			contains: map[string]string{
+				"colors.html":  "red;blue;",
+				"dynamic.html": "red;blue;",
+				"alias.html":   "red;blue;",
+				"partial.html": "red;blue;",
+				"robots.txt":   "robots v1",
+			},
+		},
+		{
+			name:     "public file",
+			changes:  map[string]string{"public/robots.txt": "robots v2"},
+			rendered: []string{},
+			contains: map[string]string{"robots.txt": "robots v2"},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			workDir := t.TempDir()
+
+			err := os.CopyFS(workDir, os.DirFS(filepath.Join(rootDir(), "tests/01-base")))
+			require.NoError(t, err)
+
+			buildDir := filepath.Join(workDir, "build")
+
+			err = os.RemoveAll(buildDir)
+			require.NoError(t, err)
+
+			writeFiles := func(files map[string]string) {
+				for filename, content := range files {
+					err := os.MkdirAll(filepath.Dir(filepath.Join(workDir, filename)), 0o700)
+					require.NoError(t, err)
+
+					err = os.WriteFile(filepath.Join(workDir, filename), []byte(content), 0o600)
+					require.NoError(t, err)
+				}
+			}
+
+			writeFiles(files)
+
+			cliTool := New(clocks, git.New("git"))
+
+			reportFilename := filepath.Join(workDir, "build-report.json")
+
+			reportOutputs := func() []string {
+				reportContent, err := os.ReadFile(reportFilename)
+				require.NoError(t, err)
+
+				report := stagen.NewBuildReport()
+
+				err = json.Unmarshal(reportContent, report)
+				require.NoError(t, err)
+
+				outputs := make([]string, 0, len(report.Pages)+len(report.PublicFiles))
+
+				for _, page := range report.Pages {
+					outputs = append(outputs, page.Output)
+				}
+
+				for _, publicFile := range report.PublicFiles {
+					outputs = append(outputs, publicFile.Output)
+				}
+
+				return outputs
+			}
+
+			stagenTool, err := cliTool.init(ctx, workDir, nil, WithReport(reportFilename))
+			require.NoError(t, err)
+
+			err = stagenTool.Build(ctx)
+			require.NoError(t, err)
+
+			// the report of the rebuild still lists the whole build
+			buildOutputs := reportOutputs()
+
+			err = os.Remove(reportFilename)
+			require.NoError(t, err)
+
+			// the pages left out of the rebuild stay removed, the rendered ones come back
+			htmlFilenames, err := filepath.Glob(filepath.Join(buildDir, "*.html"))
+			require.NoError(t, err)
+			require.NotEmpty(t, htmlFilenames)
+
+			for _, htmlFilename := range htmlFilenames {
+				err = os.Remove(htmlFilename)
+				require.NoError(t, err)
+			}
+
+			writeFiles(testCase.changes)
+
+			err = stagenTool.Rebuild(ctx, slices.Sorted(maps.Keys(testCase.changes)))
+			require.NoError(t, err)
+
+			htmlFilenames, err = filepath.Glob(filepath.Join(buildDir, "*.html"))
+			require.NoError(t, err)
+
+			rendered := make([]string, 0, len(htmlFilenames))
+
+			for _, htmlFilename := range htmlFilenames {
+				rendered = append(rendered, filepath.Base(htmlFilename))
+			}
+
+			require.Equal(t, testCase.rendered, rendered)
+			require.Equal(t, buildOutputs, reportOutputs())
+
+			for filename, expected := range testCase.contains {
+				content, err := os.ReadFile(filepath.Join(buildDir, filename))
+				require.NoError(t, err)
+				require.Contains(t, string(content), expected)
+			}
+
+			requireBuildLink(t, workDir)
+		})
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	clocks := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	workDir := t.TempDir()
+
+	err := os.CopyFS(workDir, os.DirFS(filepath.Join(rootDir(), "tests/01-base")))
+	require.NoError(t, err)
+
+	reportFilename := filepath.Join(workDir, "build-report.json")
+
+	cliTool := New(clocks, git.New("git"))
+
+	err = cliTool.Build(ctx, workDir, WithReport(reportFilename))
+	require.NoError(t, err)
+
+	reportContent, err := os.ReadFile(reportFilename)
+	require.NoError(t, err)
+
+	report := stagen.NewBuildReport()
+
+	err = json.Unmarshal(reportContent, report)
+	require.NoError(t, err)
+
+	require.Equal(t, stagen.Version, report.Version)
+	require.Equal(t, clocks.Now(), report.BuildTime.UTC())
+	require.Equal(t, stagen.BuildReportStatusSuccess, report.Status)
+	require.Empty(t, report.Error)
+	require.Len(t, report.Pages, 1)
+	require.Equal(t, "pages/index.md", report.Pages[0].Source)
+	require.Equal(t, "default", report.Pages[0].Theme)
+	require.Equal(t, "index.html", report.Pages[0].Output)
+	require.Positive(t, report.Pages[0].Bytes)
+	require.Empty(t, report.PublicFiles)
+	require.Empty(t, report.Generators)
+
+	generatorsDir := t.TempDir()
+
+	err = os.CopyFS(generatorsDir, os.DirFS(filepath.Join(rootDir(), "tests/05-generators")))
+	require.NoError(t, err)
+
+	err = cliTool.Build(ctx, generatorsDir, WithReport(reportFilename))
+	require.NoError(t, err)
+
+	reportContent, err = os.ReadFile(reportFilename)
+	require.NoError(t, err)
+
+	report = stagen.NewBuildReport()
+
+	err = json.Unmarshal(reportContent, report)
+	require.NoError(t, err)
+
+	require.Equal(t, []stagen.BuildReportGenerator{
+		{Name: "colors", Pages: 3, Skipped: 0},
+		{Name: "colors_agg", Pages: 3, Skipped: 0},
+		{Name: "ext1", Pages: 2, Skipped: 0},
+		{Name: "theme", Pages: 1, Skipped: 0},
+	}, report.Generators)
+}
+
+func TestBuildReportOnFailure(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	clocks := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	workDir := t.TempDir()
+
+	err := os.CopyFS(workDir, os.DirFS(filepath.Join(rootDir(), "tests/01-base")))
+	require.NoError(t, err)
+
+	err = os.WriteFile(
+		filepath.Join(workDir, "themes/default/config.yaml"),
+		[]byte("---\nagg_dicts:\n  - name: tags\n    keys: [tags]\n"),
+		0o600,
+	)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(workDir, "pages/tagged.md"), []byte("---\ntags: 42\n---\nTagged"), 0o600)
+	require.NoError(t, err)
+
+	reportFilename := filepath.Join(workDir, "build-report.json")
+
+	cliTool := New(clocks, git.New("git"))
+
+	readReport := func() *stagen.BuildReport {
+		reportContent, err := os.ReadFile(reportFilename)
+		require.NoError(t, err)
+
+		report := stagen.NewBuildReport()
+
+		require.NoError(t, json.Unmarshal(reportContent, report))
+
+		return report
+	}
+
+	// the warnings that fail a strict build are reported
+	err = cliTool.Build(ctx, workDir, WithReport(reportFilename), WithStrict(true))
+	require.ErrorIs(t, err, stagen.ErrStrict)
+	require.NoDirExists(t, filepath.Join(workDir, "build"))
 
-			workDir := testCase.testDir
+	report := readReport()
+	require.Equal(t, stagen.BuildReportStatusFailed, report.Status)
+	require.Contains(t, report.Error, "Unsupported variable 'tags' type: int")
+	require.Equal(t, []stagen.BuildReportWarning{
+		{File: "pages/tagged.md", Message: "Unsupported variable 'tags' type: int (42)"},
+	}, report.Warnings)
 
-			buildDir := filepath.Join(workDir, "build")
-			checkDir := filepath.Join(workDir, "_check")
+	// a render error is reported too
+	err = os.WriteFile(filepath.Join(workDir, "pages/broken.html"), []byte("{{ .Broken"), 0o600)
+	require.NoError(t, err)
 
-			err := os.RemoveAll(buildDir)
-			require.NoError(t, err)
+	err = cliTool.Build(ctx, workDir, WithReport(reportFilename))
+	require.Error(t, err)
 
-			t.Cleanup(func() {
-				if !t.Failed() {
-					err = os.RemoveAll(buildDir)
-					require.NoError(t, err)
-				}
-			})
+	report = readReport()
+	require.Equal(t, stagen.BuildReportStatusFailed, report.Status)
+	require.Equal(t, err.Error(), report.Error)
+}
 
-			gitTool := git.New("git")
+func TestCheck(t *testing.T) {
+	t.Parallel()
 
-			cliTool := New(clocks, gitTool)
+	ctx := context.Background()
 
-			err = cliTool.Build(ctx, workDir)
-			require.NoError(t, err)
+	clocks := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
 
-			diffs, err := DiffDirs(buildDir, checkDir)
-			require.NoError(t, err)
+	workDir := filepath.Join(rootDir(), "tests/10-check")
 
-			if len(diffs) > 0 {
-				t.Log(strings.Join(diffs, "\n"))
-			}
+	cliTool := New(clocks, git.New("git"))
 
-			require.Empty(t, diffs)
-		})
+	err := cliTool.Check(ctx, workDir)
+	require.ErrorIs(t, err, ErrCheckFailed)
+
+	stagenTool, err := cliTool.init(ctx, workDir, nil)
+	require.NoError(t, err)
+
+	problems, err := stagenTool.Check(ctx)
+	require.NoError(t, err)
+
+	problemsFiles := make([]string, 0, len(problems))
+
+	for _, problem := range problems {
+		problemsFiles = append(problemsFiles, problem.File)
 	}
+
+	require.Equal(t, []string{
+		"config.yaml",
+		"config.yaml",
+		"config.yaml",
+		"i18n/en.yaml",
+		"pages/broken-front-matter.md",
+		"pages/index.md",
+		"pages/invalid-paginate-collection.md",
+		"pages/invalid-paginate-size.md",
+		"pages/missing-layout.md",
+		"pages/missing-templates.md",
+		"pages/missing-templates.md",
+		"pages/missing-theme.md",
+	}, problemsFiles)
+
+	require.Contains(t, problems[0].Message, "duplicate code 'en'")
+	require.Contains(t, problems[1].Message, "unknown generator source")
+	require.Equal(t, "Page 'same/page' is generated more than once by generator 'same'", problems[2].Message)
+	require.Contains(t, problems[3].Message, "load i18n")
+	require.Contains(t, problems[5].Message, "page already exists")
+	require.Contains(t, problems[6].Message, "collection 'Missing' is not a list")
+	require.Contains(t, problems[7].Message, "size must be positive")
+	require.Contains(t, problems[8].Message, "layout 'missing' not found")
+	require.Contains(t, problems[9].Message, "import 'missing' not found")
+	require.Contains(t, problems[10].Message, "include 'missing' not found")
+
+	require.NoDirExists(t, filepath.Join(workDir, "build"))
+}
+
+func TestBuildStrict(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	clocks := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	workDir := t.TempDir()
+
+	err := os.CopyFS(workDir, os.DirFS(filepath.Join(rootDir(), "tests/01-base")))
+	require.NoError(t, err)
+
+	cliTool := New(clocks, git.New("git"))
+
+	// the temp dir is not a git repository, which is not a problem of the project
+	err = cliTool.Build(ctx, workDir, WithStrict(true))
+	require.NoError(t, err)
+
+	err = os.RemoveAll(filepath.Join(workDir, "build"))
+	require.NoError(t, err)
+
+	err = os.WriteFile(
+		filepath.Join(workDir, "themes/default/config.yaml"),
+		[]byte("---\nagg_dicts:\n  - name: tags\n    keys: [tags]\n"),
+		0o600,
+	)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(workDir, "pages/tagged.md"), []byte("---\ntags: 42\n---\nTagged"), 0o600)
+	require.NoError(t, err)
+
+	err = cliTool.Build(ctx, workDir, WithStrict(true))
+	require.ErrorIs(t, err, stagen.ErrStrict)
+	require.ErrorContains(t, err, "pages/tagged.md: Unsupported variable 'tags' type: int")
+	require.NoFileExists(t, filepath.Join(workDir, "build/index.html"))
+
+	err = cliTool.Build(ctx, workDir)
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(workDir, "build/index.html"))
 }
 
 func DiffDirs(buildDir, checkDir string) ([]string, error) {
-	buildDir = filepath.Clean(buildDir)
-	checkDir = filepath.Clean(checkDir)
+	// the build directories are links to their last build
+	buildDir, err := filepath.EvalSymlinks(buildDir)
+	if err != nil {
+		return nil, err
+	}
+
+	checkDir, err = filepath.EvalSymlinks(checkDir)
+	if err != nil {
+		return nil, err
+	}
 
 	checkMap := make(map[string]fs.DirEntry)
 	buildMap := make(map[string]fs.DirEntry)
 
 	var diffs []string
 
-	err := filepath.WalkDir(checkDir, func(path string, dirEntry fs.DirEntry, err error) error {
+	err = filepath.WalkDir(checkDir, func(path string, dirEntry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -344,3 +1300,406 @@ func equalBytes(buf1 []byte, buf2 []byte) bool {
 
 	return true
 }
+
+func TestBuildSourceErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	clocks := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name    string
+		testDir string
+		files   map[string]string
+		errFile string
+		line    int
+		column  int
+		excerpt string
+	}{
+		{
+			name:    "page content",
+			testDir: "tests/01-base",
+			files: map[string]string{
+				"pages/broken.html": "---\ntitle: Broken\n---\n<p>One</p>\n<p>{{ .Page.Missing }</p>\n",
+			},
+			errFile: "pages/broken.html",
+			line:    5,
+			column:  0,
+			excerpt: "> 5 | <p>{{ .Page.Missing }</p>",
+		},
+		{
+			name:    "page execution",
+			testDir: "tests/01-base",
+			files: map[string]string{
+				"pages/broken.html": "---\ntitle: Broken\n---\n<p>{{ index .Page.Title 10 }}</p>\n",
+			},
+			errFile: "pages/broken.html",
+			line:    4,
+			column:  7,
+			excerpt: "> 4 | <p>{{ index .Page.Title 10 }}</p>\n    |       ^",
+		},
+		{
+			name:    "front matter",
+			testDir: "tests/01-base",
+			files: map[string]string{
+				"pages/broken.html": "---\ntitle: Broken\ntags: [a\n---\n<p>One</p>\n",
+			},
+			errFile: "pages/broken.html",
+			line:    3,
+			column:  0,
+			excerpt: "tags: [a",
+		},
+		{
+			name:    "macro content",
+			testDir: "tests/04-macros",
+			files: map[string]string{
+				"pages/index.md": "Hello, world!\n<Ext1 a=420>\n  <p>This is a content</p>\n  <p>{{ .a }</p>\n</Ext1>\n",
+			},
+			errFile: "pages/index.md",
+			line:    4,
+			column:  0,
+			excerpt: "> 4 |   <p>{{ .a }</p>",
+		},
+		{
+			name:    "macro content execution",
+			testDir: "tests/04-macros",
+			files: map[string]string{
+				"pages/index.md": "Hello, world!\n<Ext1 a=420>\n  <p>{{ index \"abc\" 10 }}</p>\n</Ext1>\n",
+			},
+			errFile: "pages/index.md",
+			line:    3,
+			column:  9,
+			excerpt: "> 3 |   <p>{{ index \"abc\" 10 }}</p>\n    |         ^",
+		},
+		{
+			name:    "import",
+			testDir: "tests/03-imports",
+			files: map[string]string{
+				"templates/imports/example.html.tmpl": "{{ define \"macro:Example\" }}\n<p>{{ .a }</p>\n{{ end }}\n",
+			},
+			errFile: "templates/imports/example.html.tmpl",
+			line:    2,
+			column:  0,
+			excerpt: "> 2 | <p>{{ .a }</p>",
+		},
+		{
+			name:    "include",
+			testDir: "tests/01-base",
+			files: map[string]string{
+				"pages/card.html":                   `{{ include "card" (dict "title" "x") }}`,
+				"templates/includes/card.html.tmpl": "{{ define \"card\" }}\n<p>{{ index .title 3 }}</p>\n{{ end }}\n",
+			},
+			errFile: "templates/includes/card.html.tmpl",
+			line:    2,
+			column:  7,
+			excerpt: "> 2 | <p>{{ index .title 3 }}</p>\n    |       ^",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			workDir := t.TempDir()
+
+			err := os.CopyFS(workDir, os.DirFS(filepath.Join(rootDir(), testCase.testDir)))
+			require.NoError(t, err)
+
+			for filename, content := range testCase.files {
+				require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(workDir, filename)), 0o755))
+				require.NoError(t, os.WriteFile(filepath.Join(workDir, filename), []byte(content), 0o600))
+			}
+
+			cliTool := New(clocks, git.New("git"))
+
+			err = cliTool.Build(ctx, workDir)
+
+			var sourceErr *source_error.SourceError
+
+			require.ErrorAs(t, err, &sourceErr)
+			require.Equal(t, testCase.errFile, sourceErr.File)
+			require.Equal(t, testCase.line, sourceErr.Line)
+			require.Equal(t, testCase.column, sourceErr.Column)
+			require.Contains(t, sourceErr.Excerpt, testCase.excerpt)
+		})
+	}
+}
+
+func TestBuildReproducible(t *testing.T) {
+	t.Setenv(sourceDateEpochEnv, "1000000000")
+
+	ctx := context.Background()
+
+	workDir := t.TempDir()
+
+	err := os.CopyFS(workDir, os.DirFS(filepath.Join(rootDir(), "tests/01-base")))
+	require.NoError(t, err)
+
+	err = os.WriteFile(
+		filepath.Join(workDir, "themes/default/config.yaml"),
+		[]byte("---\nagg_dicts:\n  - name: tags\n    keys: [tags]\n"),
+		0o600,
+	)
+	require.NoError(t, err)
+
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		err = os.WriteFile(
+			filepath.Join(workDir, "pages", name+".md"),
+			[]byte("---\ntags: [one, two]\n---\n"+name),
+			0o600,
+		)
+		require.NoError(t, err)
+	}
+
+	err = os.WriteFile(
+		filepath.Join(workDir, "pages/list.html"),
+		[]byte(`{{ range index .AggDictsData "tags" "tags" "one" }}{{ .Id }},{{ end }} {{ .System.BuildTime.Unix }} {{ .System.Now.Unix }}`),
+		0o600,
+	)
+	require.NoError(t, err)
+
+	// only the build time is pinned, Now still comes from the clock
+	cliTool := New(newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)), git.New("git"))
+
+	require.NoError(t, cliTool.Build(ctx, workDir, WithOutput("build-a")))
+	require.NoError(t, cliTool.Build(ctx, workDir, WithOutput("build-b")))
+
+	listContent, err := os.ReadFile(filepath.Join(workDir, "build-a/list.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(listContent), "a,b,c,d,e,f, 1000000000 1735689600")
+
+	buildFiles := func(buildDir string) map[string]string {
+		files := make(map[string]string)
+
+		buildDir, err := filepath.EvalSymlinks(buildDir)
+		require.NoError(t, err)
+
+		err = filepath.WalkDir(buildDir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			relPath, err := filepath.Rel(buildDir, path)
+			if err != nil {
+				return err
+			}
+
+			files[relPath] = string(content)
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		return files
+	}
+
+	require.Equal(t, buildFiles(filepath.Join(workDir, "build-a")), buildFiles(filepath.Join(workDir, "build-b")))
+}
+
+func TestBuildJobs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	clocks := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	workDir := t.TempDir()
+
+	err := os.CopyFS(workDir, os.DirFS(filepath.Join(rootDir(), "tests/01-base")))
+	require.NoError(t, err)
+
+	for index := range 20 {
+		err = os.WriteFile(
+			filepath.Join(workDir, "pages", fmt.Sprintf("page-%02d.md", index)),
+			[]byte(fmt.Sprintf("Page %d of {{ len .Pages }}", index)),
+			0o600,
+		)
+		require.NoError(t, err)
+	}
+
+	cliTool := New(clocks, git.New("git"))
+
+	require.NoError(t, cliTool.Build(ctx, workDir, WithJobs(1), WithOutput(filepath.Join(workDir, "build-1"))))
+	require.NoError(t, cliTool.Build(ctx, workDir, WithJobs(8), WithOutput(filepath.Join(workDir, "build-8"))))
+
+	diffs, err := DiffDirs(filepath.Join(workDir, "build-8"), filepath.Join(workDir, "build-1"))
+	require.NoError(t, err)
+	require.Empty(t, diffs)
+
+	// the errors of the pages rendered in parallel come in the order of the pages
+	for _, name := range []string{"broken-c", "broken-a", "broken-b"} {
+		err = os.WriteFile(filepath.Join(workDir, "pages", name+".html"), []byte(`{{ template "missing" }}`), 0o600)
+		require.NoError(t, err)
+	}
+
+	var firstErr string
+
+	for range 5 {
+		err = cliTool.Build(ctx, workDir, WithJobs(8), WithOutput(filepath.Join(workDir, "build-8")))
+		require.Error(t, err)
+
+		if firstErr == "" {
+			firstErr = err.Error()
+		}
+
+		require.Equal(t, firstErr, err.Error())
+	}
+
+	aIndex := strings.Index(firstErr, "'broken-a'")
+	bIndex := strings.Index(firstErr, "'broken-b'")
+	cIndex := strings.Index(firstErr, "'broken-c'")
+
+	require.True(t, aIndex >= 0 && aIndex < bIndex && bIndex < cIndex, firstErr)
+}
+
+func TestBuildPageTextError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	clocks := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	workDir := t.TempDir()
+
+	err := os.CopyFS(workDir, os.DirFS(filepath.Join(rootDir(), "tests/01-base")))
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(workDir, "pages/about.md"), []byte("## First heading\n\nAbout"), 0o600)
+	require.NoError(t, err)
+
+	// the texts are not there while the texts are rendered, so this content fails only then
+	err = os.WriteFile(filepath.Join(workDir, "pages/uses-toc.html"), []byte(`{{ (index (index .Pages "about").Toc 0).Text }}`), 0o600)
+	require.NoError(t, err)
+
+	reportFilename := filepath.Join(workDir, "build-report.json")
+
+	cliTool := New(clocks, git.New("git"))
+
+	err = cliTool.Build(ctx, workDir, WithReport(reportFilename))
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(workDir, "build/uses-toc.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "First heading")
+
+	reportContent, err := os.ReadFile(reportFilename)
+	require.NoError(t, err)
+
+	report := stagen.NewBuildReport()
+
+	err = json.Unmarshal(reportContent, report)
+	require.NoError(t, err)
+
+	require.Len(t, report.Warnings, 1)
+	require.Equal(t, "pages/uses-toc.html", report.Warnings[0].File)
+	require.Contains(t, report.Warnings[0].Message, "Page 'uses-toc' has no summary, toc and word count")
+}
+
+func TestBuildPermalinkErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	clocks := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	workDir := t.TempDir()
+
+	err := os.CopyFS(workDir, os.DirFS(filepath.Join(rootDir(), "tests/11-permalinks")))
+	require.NoError(t, err)
+
+	err = os.RemoveAll(filepath.Join(workDir, "build"))
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(workDir, "pages/broken.md"), []byte("---\npermalink: /:missing/\n---\n"), 0o600)
+	require.NoError(t, err)
+
+	cliTool := New(clocks, git.New("git"))
+
+	err = cliTool.Build(ctx, workDir)
+	require.ErrorIs(t, err, stagen.ErrPermalinkUnknownPlaceholder)
+}
+
+func TestBuildAliasErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	clocks := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	workDir := t.TempDir()
+
+	err := os.CopyFS(workDir, os.DirFS(filepath.Join(rootDir(), "tests/12-aliases")))
+	require.NoError(t, err)
+
+	err = os.RemoveAll(filepath.Join(workDir, "build"))
+	require.NoError(t, err)
+
+	err = os.WriteFile(
+		filepath.Join(workDir, "pages/docs/new.md"),
+		[]byte("---\naliases: [/docs/old/, /]\n---\nNew"),
+		0o600,
+	)
+	require.NoError(t, err)
+
+	cliTool := New(clocks, git.New("git"))
+
+	err = cliTool.Build(ctx, workDir)
+	require.ErrorIs(t, err, stagen.ErrPageAlreadyExists)
+}
+
+func TestBuildPaginationErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		files    map[string]string
+		errorMsg string
+	}{
+		{
+			name:     "alias_over_pager",
+			files:    map[string]string{"pages/old.md": "---\naliases: [/blog/page/2/]\n---\nOld"},
+			errorMsg: "alias '/blog/page/2/' is built by page 'blog/page/2'",
+		},
+		{
+			name:     "page_over_pager",
+			files:    map[string]string{"pages/blog/page/2/index.html": "Page"},
+			errorMsg: "page 2 is built by page 'blog/page/2/index'",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			clocks := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+			workDir := t.TempDir()
+
+			err := os.CopyFS(workDir, os.DirFS(filepath.Join(rootDir(), "tests/22-pagination")))
+			require.NoError(t, err)
+
+			err = os.RemoveAll(filepath.Join(workDir, "build"))
+			require.NoError(t, err)
+
+			for filename, content := range testCase.files {
+				require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(workDir, filename)), 0o755))
+				require.NoError(t, os.WriteFile(filepath.Join(workDir, filename), []byte(content), 0o600))
+			}
+
+			cliTool := New(clocks, git.New("git"))
+
+			err = cliTool.Build(ctx, workDir)
+			require.ErrorIs(t, err, stagen.ErrPageAlreadyExists)
+			require.ErrorContains(t, err, testCase.errorMsg)
+			require.NoDirExists(t, filepath.Join(workDir, "build"))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"github.com/stagens/stagen/internal/config"
+)
+
+type Option func(opts *options)
+
+type options struct {
+	configFile      string
+	configOverrides []func(cfg *config.Config)
+}
+
+func newOptions(opts []Option) *options {
+	result := &options{
+		configFile:      "",
+		configOverrides: nil,
+	}
+
+	for _, opt := range opts {
+		opt(result)
+	}
+
+	return result
+}
+
+func (o *options) apply(cfg *config.Config) {
+	for _, configOverride := range o.configOverrides {
+		configOverride(cfg)
+	}
+}
+
+func WithConfigFile(configFile string) Option {
+	return func(opts *options) {
+		opts.configFile = configFile
+	}
+}
+
+func WithOutput(output string) Option {
+	return func(opts *options) {
+		opts.configOverrides = append(opts.configOverrides, func(cfg *config.Config) {
+			cfg.Stagen.SettingsValue.BuildDirValue = output
+		})
+	}
+}
+
+func WithEnv(env string) Option {
+	return func(opts *options) {
+		opts.configOverrides = append(opts.configOverrides, func(cfg *config.Config) {
+			cfg.Stagen.EnvValue = env
+		})
+	}
+}
+
+func WithBaseUrl(baseUrl string) Option {
+	return func(opts *options) {
+		opts.configOverrides = append(opts.configOverrides, func(cfg *config.Config) {
+			cfg.Site.BaseUrlValue = baseUrl
+		})
+	}
+}
+
+func WithJobs(jobs int) Option {
+	return func(opts *options) {
+		opts.configOverrides = append(opts.configOverrides, func(cfg *config.Config) {
+			cfg.Stagen.SettingsValue.JobsValue = jobs
+		})
+	}
+}
+
+func WithDrafts(drafts bool) Option {
+	return func(opts *options) {
+		opts.configOverrides = append(opts.configOverrides, func(cfg *config.Config) {
+			cfg.Stagen.SettingsValue.BuildDraftsValue = drafts
+		})
+	}
+}
+
+func WithFuture(future bool) Option {
+	return func(opts *options) {
+		opts.configOverrides = append(opts.configOverrides, func(cfg *config.Config) {
+			cfg.Stagen.SettingsValue.BuildFutureValue = future
+		})
+	}
+}
+
+func WithReport(report string) Option {
+	return func(opts *options) {
+		opts.configOverrides = append(opts.configOverrides, func(cfg *config.Config) {
+			cfg.Stagen.SettingsValue.BuildReportValue = report
+		})
+	}
+}
+
+func WithStrict(strict bool) Option {
+	return func(opts *options) {
+		opts.configOverrides = append(opts.configOverrides, func(cfg *config.Config) {
+			cfg.Stagen.SettingsValue.StrictValue = strict
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/stagens/stagen/pkg/stagen"
+)
+
+// sourceDateEpochEnv pins the build time for reproducible builds, see https://reproducible-builds.org/specs/source-date-epoch/
+const sourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
+var ErrInvalidSourceDateEpoch = errors.New("invalid " + sourceDateEpochEnv)
+
+// sourceDateEpochOptions pins System.BuildTime only, the clock keeps serving Now, time sources and rebuilds
+func sourceDateEpochOptions() ([]stagen.Option, error) {
+	sourceDateEpoch := os.Getenv(sourceDateEpochEnv)
+	if sourceDateEpoch == "" {
+		return nil, nil
+	}
+
+	seconds, err := strconv.ParseInt(sourceDateEpoch, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidSourceDateEpoch, sourceDateEpoch, err)
+	}
+
+	return []stagen.Option{
+		stagen.WithBuildTime(time.Unix(seconds, 0).UTC()),
+	}, nil
+}
@@ -43,10 +43,10 @@ func RootDir() string {
 	return cwd
 }
 
-func configFile() string {
+func ConfigFile(dir string) string {
 	configFilename := os.Getenv("STAGEN_CONFIG_FILE")
 	if configFilename == "" {
-		configFilename = filepath.Join(RootDir(), "config.yaml")
+		configFilename = filepath.Join(dir, "config.yaml")
 	}
 
 	return configFilename
@@ -58,7 +58,7 @@ func NewConfigFromEnv() (*Config, error) {
 
 func NewConfigFromFile(filename string) (*Config, error) {
 	if filename == "" {
-		filename = configFile()
+		filename = ConfigFile(RootDir())
 	}
 
 	return coreConfig.NewConfig[Config](filename)
@@ -66,7 +66,7 @@ func NewConfigFromFile(filename string) (*Config, error) {
 
 func LoadConfig(filename string) *Config {
 	if filename == "" {
-		filename = configFile()
+		filename = ConfigFile(RootDir())
 	}
 
 	return coreConfig.LoadConfig[Config](filename)
@@ -2,17 +2,41 @@ package git
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/pixality-inc/golang-core/cli"
 	"github.com/pixality-inc/golang-core/logger"
 )
 
+const (
+	logRecordSeparator = "\x1e"
+	logFieldSeparator  = "\x1f"
+	logFieldsCount     = 6
+)
+
+var ErrInvalidLogRecord = errors.New("invalid log record")
+
+type Commit struct {
+	Hash        string
+	AuthorName  string
+	AuthorEmail string
+	AuthorDate  time.Time
+	Subject     string
+	Files       []string
+}
+
 type Git interface {
 	HasGit(ctx context.Context) bool
+	IsRepository(ctx context.Context, workDir string) bool
 	Init(ctx context.Context, workDir string) error
 	Clone(ctx context.Context, workDir string, url string) error
 	SubmoduleAdd(ctx context.Context, workDir string, url string, dest string) error
+	Log(ctx context.Context, workDir string, paths ...string) ([]Commit, error)
 }
 
 type Impl struct {
@@ -35,6 +59,26 @@ func (g *Impl) HasGit(ctx context.Context) bool {
 	return err == nil
 }
 
+func (g *Impl) IsRepository(_ context.Context, workDir string) bool {
+	dir, err := filepath.Abs(workDir)
+	if err != nil {
+		return false
+	}
+
+	for {
+		if _, err = os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return true
+		}
+
+		parentDir := filepath.Dir(dir)
+		if parentDir == dir {
+			return false
+		}
+
+		dir = parentDir
+	}
+}
+
 func (g *Impl) Init(ctx context.Context, workDir string) error {
 	_, err := g.exec(ctx, workDir, "init")
 
@@ -53,6 +97,63 @@ func (g *Impl) SubmoduleAdd(ctx context.Context, workDir string, url string, des
 	return err
 }
 
+func (g *Impl) Log(ctx context.Context, workDir string, paths ...string) ([]Commit, error) {
+	args := []string{
+		"-c", "core.quotepath=off",
+		"log",
+		"--relative",
+		"--name-only",
+		"--format=" + logRecordSeparator + strings.Join([]string{"%H", "%an", "%ae", "%aI", "%s", ""}, logFieldSeparator),
+		"--",
+	}
+
+	args = append(args, paths...)
+
+	result, err := g.exec(ctx, workDir, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	records := strings.Split(result, logRecordSeparator)
+
+	commits := make([]Commit, 0, len(records))
+
+	for _, record := range records {
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+
+		fields := strings.SplitN(record, logFieldSeparator, logFieldsCount)
+		if len(fields) != logFieldsCount {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidLogRecord, record)
+		}
+
+		authorDate, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("%w: author date %q: %w", ErrInvalidLogRecord, fields[3], err)
+		}
+
+		files := make([]string, 0)
+
+		for file := range strings.SplitSeq(fields[5], "\n") {
+			if file != "" {
+				files = append(files, file)
+			}
+		}
+
+		commits = append(commits, Commit{
+			Hash:        fields[0],
+			AuthorName:  fields[1],
+			AuthorEmail: fields[2],
+			AuthorDate:  authorDate,
+			Subject:     fields[4],
+			Files:       files,
+		})
+	}
+
+	return commits, nil
+}
+
 func (g *Impl) exec(ctx context.Context, workDir string, args ...string) (string, error) {
 	result, err := g.cli.Exec(ctx, args, cli.WithWorkDir(workDir))
 	if err != nil {
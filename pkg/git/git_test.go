@@ -0,0 +1,141 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testAuthor struct {
+	name  string
+	email string
+}
+
+func runGit(t *testing.T, workDir string, author testAuthor, date time.Time, args ...string) string {
+	t.Helper()
+
+	cmd := exec.CommandContext(context.Background(), "git", args...)
+	cmd.Dir = workDir
+	cmd.Env = append(
+		os.Environ(),
+		"GIT_CONFIG_GLOBAL=/dev/null",
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME="+author.name,
+		"GIT_AUTHOR_EMAIL="+author.email,
+		"GIT_AUTHOR_DATE="+date.Format(time.RFC3339),
+		"GIT_COMMITTER_NAME="+author.name,
+		"GIT_COMMITTER_EMAIL="+author.email,
+		"GIT_COMMITTER_DATE="+date.Format(time.RFC3339),
+	)
+
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	return strings.TrimSpace(string(output))
+}
+
+func TestLog(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	gitTool := New("git")
+
+	if !gitTool.HasGit(ctx) {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	workDir := filepath.Join(repoDir, "site")
+
+	alice := testAuthor{name: "Alice", email: "alice@example.com"}
+	bob := testAuthor{name: "Bob Builder", email: "bob@example.com"}
+
+	firstDate := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	secondDate := time.Date(2024, 2, 3, 11, 30, 0, 0, time.FixedZone("", 2*60*60))
+
+	require.NoError(t, gitTool.Init(ctx, repoDir))
+
+	writeFile := func(filename string, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(workDir, filename)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(workDir, filename), []byte(content), 0o600))
+	}
+
+	writeFile("pages/index.md", "Index")
+	writeFile("pages/über.md", "Unicode")
+	writeFile("readme.md", "Not a page")
+
+	runGit(t, repoDir, alice, firstDate, "add", ".")
+	runGit(t, repoDir, alice, firstDate, "commit", "-q", "-m", "Add pages")
+
+	firstHash := runGit(t, repoDir, alice, firstDate, "rev-parse", "HEAD")
+
+	writeFile("pages/index.md", "Index changed")
+	writeFile("pages/blog/post.md", "Post")
+
+	runGit(t, repoDir, bob, secondDate, "add", ".")
+	runGit(t, repoDir, bob, secondDate, "commit", "-q", "-m", "Update index: add post\n\nWith a body")
+
+	secondHash := runGit(t, repoDir, bob, secondDate, "rev-parse", "HEAD")
+
+	require.True(t, gitTool.IsRepository(ctx, workDir))
+
+	commits, err := gitTool.Log(ctx, workDir, "pages")
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+
+	// author dates keep their offset, the location itself depends on the parser
+	for index, date := range []time.Time{secondDate, firstDate} {
+		require.True(t, date.Equal(commits[index].AuthorDate))
+
+		_, expectedOffset := date.Zone()
+		_, offset := commits[index].AuthorDate.Zone()
+		require.Equal(t, expectedOffset, offset)
+
+		commits[index].AuthorDate = date
+	}
+
+	require.Equal(t, []Commit{
+		{
+			Hash:        secondHash,
+			AuthorName:  "Bob Builder",
+			AuthorEmail: "bob@example.com",
+			AuthorDate:  secondDate,
+			Subject:     "Update index: add post",
+			Files:       []string{"pages/blog/post.md", "pages/index.md"},
+		},
+		{
+			Hash:        firstHash,
+			AuthorName:  "Alice",
+			AuthorEmail: "alice@example.com",
+			AuthorDate:  firstDate,
+			Subject:     "Add pages",
+			Files:       []string{"pages/index.md", "pages/über.md"},
+		},
+	}, commits)
+}
+
+func TestLogOutsideRepository(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	gitTool := New("git")
+
+	if !gitTool.HasGit(ctx) {
+		t.Skip("git is not installed")
+	}
+
+	workDir := t.TempDir()
+
+	require.False(t, gitTool.IsRepository(ctx, workDir))
+
+	_, err := gitTool.Log(ctx, workDir, "pages")
+	require.Error(t, err)
+}
@@ -3,20 +3,32 @@ package html_preprocessor
 import (
 	"bytes"
 	"context"
+	"sync/atomic"
 
 	"github.com/stagens/stagen/pkg/html_tokenizer"
 )
 
 var AttributesWithoutValue = []string{"checked", "required", "crossorigin"}
 
+type Extra struct {
+	Name    string
+	Line    int
+	Content []byte
+}
+
+type PreprocessResult struct {
+	Content []byte
+	Extras  []Extra
+}
+
 type HtmlPreprocessor interface {
-	Preprocess(ctx context.Context, content []byte) ([]byte, []byte, error)
+	Preprocess(ctx context.Context, content []byte) (*PreprocessResult, error)
 	Postprocess(ctx context.Context, content []byte) ([]byte, error)
 }
 
 type Impl struct {
 	htmlTokenizer          html_tokenizer.Tokenizer
-	increment              int
+	increment              atomic.Int64
 	macroWrapper           MacroWrapper
 	attributesWithoutValue []string
 }
@@ -29,24 +41,29 @@ func New(
 ) *Impl {
 	return &Impl{
 		htmlTokenizer:          html_tokenizer.NewTokenizer(addClosingTags, withoutClosingTags),
-		increment:              0,
+		increment:              atomic.Int64{},
 		macroWrapper:           macroWrapper,
 		attributesWithoutValue: attributesWithoutValue,
 	}
 }
 
-func (p *Impl) Preprocess(ctx context.Context, content []byte) ([]byte, []byte, error) {
+func (p *Impl) Preprocess(ctx context.Context, content []byte) (*PreprocessResult, error) {
 	tokens, err := p.htmlTokenizer.Tokenize(ctx, bytes.NewReader(content))
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	tokensRenderResult, err := p.renderTokens(tokens)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+
+	result := &PreprocessResult{
+		Content: tokensRenderResult.content,
+		Extras:  tokensRenderResult.extras,
 	}
 
-	return tokensRenderResult.extras, tokensRenderResult.content, nil
+	return result, nil
 }
 
 func (p *Impl) Postprocess(ctx context.Context, content []byte) ([]byte, error) {
@@ -1,6 +1,7 @@
 package html_preprocessor
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"strconv"
@@ -51,24 +52,32 @@ func (p *Impl) renderToken(token html_tokenizer.Token) (*TokenRenderResult, erro
 
 			macroName := originalTag
 
-			p.increment++
+			increment := p.increment.Add(1)
 
-			contentMacroName := "Content__Macro__" + macroName + "__" + strconv.Itoa(p.increment)
+			contentMacroName := "Content__Macro__" + macroName + "__" + strconv.FormatInt(increment, 10)
 
 			macroWrapperResult, err := p.macroWrapper(macroName, contentMacroName, attrs)
 			if err != nil {
 				return nil, fmt.Errorf("macro wrapper: %w", err)
 			}
 
-			result = result.AppendExtras(macroWrapperResult.Before)
+			extraContent := make([]byte, 0, len(macroWrapperResult.Before)+len(childrenResults.content)+len(macroWrapperResult.After))
 
-			result = result.AppendExtras(childrenResults.content)
+			extraContent = append(extraContent, macroWrapperResult.Before...)
+			extraContent = append(extraContent, childrenResults.content...)
+			extraContent = append(extraContent, macroWrapperResult.After...)
 
-			result = result.AppendExtras(macroWrapperResult.After)
+			result = result.AppendExtras(Extra{
+				Name:    contentMacroName,
+				Line:    tok.Line() + countLines(tok.Raw()),
+				Content: extraContent,
+			})
 
-			result = result.AppendExtras(childrenResults.extras)
+			result = result.AppendExtras(childrenResults.extras...)
 
 			result = result.AppendContent(macroWrapperResult.Call)
+
+			result = result.AppendContent(linesPadding(tokenLines(tok)))
 		} else {
 			result = result.AppendContent(tok.Raw())
 
@@ -82,14 +91,45 @@ func (p *Impl) renderToken(token html_tokenizer.Token) (*TokenRenderResult, erro
 			if !tok.SelfClosing() || tok.AddClosing() {
 				result = result.AppendContent([]byte("</" + tok.Tag() + ">"))
 			}
+
+			result = result.AppendContent(linesPadding(countLines(tok.EndRaw())))
 		}
 
 		return result, nil
 
 	case *html_tokenizer.CommentToken:
-		return NewTokenRenderResult(), nil
+		return result.AppendContent(linesPadding(countLines(tok.Raw()))), nil
 
 	default:
 		return nil, fmt.Errorf("%w: '%s' (%T)", ErrUnknownTokenType, tok.Type(), tok)
 	}
 }
+
+func countLines(raw []byte) int {
+	return bytes.Count(raw, []byte("\n"))
+}
+
+// tokenLines returns the number of line breaks the token spans in the source
+func tokenLines(token html_tokenizer.Token) int {
+	lines := countLines(token.Raw())
+
+	if tagToken, ok := token.(*html_tokenizer.TagToken); ok {
+		for _, child := range tagToken.Children() {
+			lines += tokenLines(child)
+		}
+
+		lines += countLines(tagToken.EndRaw())
+	}
+
+	return lines
+}
+
+// linesPadding returns a template comment spanning the given number of lines,
+// so that line numbers of the template errors still match the source
+func linesPadding(lines int) []byte {
+	if lines == 0 {
+		return nil
+	}
+
+	return []byte("{{/*" + strings.Repeat("\n", lines) + "*/}}")
+}
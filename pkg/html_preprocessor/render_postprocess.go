@@ -120,7 +120,7 @@ func (p *Impl) postprocessBlockquote(token *html_tokenizer.TagToken) (*html_toke
 		newDetailsChildren = append(newDetailsChildren, others...)
 
 		newDetails := html_tokenizer.NewTagToken(
-			html_tokenizer.NewHtmlToken(details.Token(), details.Raw()),
+			html_tokenizer.NewHtmlToken(details.Token(), details.Raw(), details.Line()),
 			details.Position(),
 			nil,
 			details.SelfClosing(),
@@ -134,7 +134,7 @@ func (p *Impl) postprocessBlockquote(token *html_tokenizer.TagToken) (*html_toke
 	}
 
 	newToken := html_tokenizer.NewTagToken(
-		html_tokenizer.NewHtmlToken(token.Token(), token.Raw()),
+		html_tokenizer.NewHtmlToken(token.Token(), token.Raw(), token.Line()),
 		token.Position(),
 		nil,
 		token.SelfClosing(),
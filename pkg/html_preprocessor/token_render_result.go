@@ -1,19 +1,19 @@
 package html_preprocessor
 
 type TokenRenderResult struct {
-	extras  []byte
+	extras  []Extra
 	content []byte
 }
 
 func NewTokenRenderResult() *TokenRenderResult {
 	return &TokenRenderResult{
-		extras:  make([]byte, 0),
+		extras:  make([]Extra, 0),
 		content: make([]byte, 0),
 	}
 }
 
-func (tr *TokenRenderResult) AppendExtras(buf []byte) *TokenRenderResult {
-	tr.extras = append(tr.extras, buf...)
+func (tr *TokenRenderResult) AppendExtras(extras ...Extra) *TokenRenderResult {
+	tr.extras = append(tr.extras, extras...)
 
 	return tr
 }
@@ -25,7 +25,7 @@ func (tr *TokenRenderResult) AppendContent(buf []byte) *TokenRenderResult {
 }
 
 func (tr *TokenRenderResult) Append(res *TokenRenderResult) *TokenRenderResult {
-	tr.AppendExtras(res.extras)
+	tr.AppendExtras(res.extras...)
 	tr.AppendContent(res.content)
 
 	return tr
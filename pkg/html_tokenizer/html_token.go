@@ -5,11 +5,13 @@ import "golang.org/x/net/html"
 type HtmlToken struct {
 	Token html.Token
 	Raw   []byte
+	Line  int
 }
 
-func NewHtmlToken(token html.Token, raw []byte) *HtmlToken {
+func NewHtmlToken(token html.Token, raw []byte, line int) *HtmlToken {
 	return &HtmlToken{
 		Token: token,
 		Raw:   raw,
+		Line:  line,
 	}
 }
@@ -43,6 +43,10 @@ func (t *TokenImpl) Raw() []byte {
 	return t.token.Raw
 }
 
+func (t *TokenImpl) Line() int {
+	return t.token.Line
+}
+
 func (t *TokenImpl) Position() Position {
 	return t.position
 }
@@ -1,6 +1,7 @@
 package html_tokenizer
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -27,6 +28,7 @@ type State struct {
 	htmlTokenizer      *html.Tokenizer
 	position           Position
 	nextTokenBuf       *HtmlToken
+	line               int
 }
 
 func NewState(reader io.Reader, addClosingTags []string, withoutClosingTags []string) *State {
@@ -37,6 +39,7 @@ func NewState(reader io.Reader, addClosingTags []string, withoutClosingTags []st
 		reader:             reader,
 		htmlTokenizer:      html.NewTokenizer(reader),
 		position:           Position{depth: 0},
+		line:               1,
 	}
 }
 
@@ -91,7 +94,11 @@ func (s *State) nextToken(_ context.Context) (*HtmlToken, error) {
 
 	token := s.htmlTokenizer.Token()
 
-	return NewHtmlToken(token, rawCopy), nil
+	line := s.line
+
+	s.line += bytes.Count(rawCopy, []byte("\n"))
+
+	return NewHtmlToken(token, rawCopy, line), nil
 }
 
 func (s *State) parseToken(ctx context.Context, token *HtmlToken) (Token, error) {
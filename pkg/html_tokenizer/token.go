@@ -18,6 +18,7 @@ type Token interface {
 	Depth() int
 	Token() html.Token
 	Raw() []byte
+	Line() int
 	isToken() bool
 	SetParent(parent Token)
 }
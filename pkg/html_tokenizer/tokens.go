@@ -70,6 +70,14 @@ func (t *TagToken) Tag() string {
 	return t.tag
 }
 
+func (t *TagToken) EndRaw() []byte {
+	if t.endToken == nil {
+		return nil
+	}
+
+	return t.endToken.Raw
+}
+
 func (t *TagToken) Children() []Token {
 	return t.children
 }
@@ -67,11 +67,6 @@ func (m *Impl) Render(content []byte) ([]byte, error) {
 
 	doc := m.markdown.Parser().Parse(text.NewReader(content))
 
-	// tree, err := toc.Inspect(doc, content)
-	// if err != nil {
-	// 	return nil, err
-	// }
-
 	if err := m.markdown.Renderer().Render(writer, content, doc); err != nil {
 		return nil, err
 	}
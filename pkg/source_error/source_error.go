@@ -0,0 +1,107 @@
+package source_error
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const excerptContextLines = 2
+
+type SourceError struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+	Excerpt string
+	err     error
+}
+
+func New(
+	file string,
+	line int,
+	column int,
+	message string,
+	excerpt string,
+	err error,
+) *SourceError {
+	return &SourceError{
+		File:    file,
+		Line:    line,
+		Column:  column,
+		Message: message,
+		Excerpt: excerpt,
+		err:     err,
+	}
+}
+
+func (e *SourceError) Position() string {
+	position := e.File
+
+	if e.Line > 0 {
+		position += ":" + strconv.Itoa(e.Line)
+
+		if e.Column > 0 {
+			position += ":" + strconv.Itoa(e.Column)
+		}
+	}
+
+	return position
+}
+
+func (e *SourceError) Error() string {
+	return e.Position() + ": " + e.Message
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.err
+}
+
+// ExcerptOf returns the excerpt of the first SourceError in the chain of err
+func ExcerptOf(err error) string {
+	var sourceErr *SourceError
+	if !errors.As(err, &sourceErr) {
+		return ""
+	}
+
+	return sourceErr.Excerpt
+}
+
+// Excerpt returns the lines around line of content with a marker under column,
+// firstLine is the line of the file content starts at, line and column start from 1,
+// content may start before the file, these lines are not shown
+func Excerpt(content string, firstLine int, line int, column int) string {
+	lines := strings.Split(content, "\n")
+
+	firstLine = max(0, firstLine)
+	lastLine := firstLine + len(lines) - 1
+
+	if line < max(1, firstLine) || line > lastLine {
+		return ""
+	}
+
+	fromLine := max(firstLine, 1, line-excerptContextLines)
+	toLine := min(lastLine, line+excerptContextLines)
+
+	numberWidth := len(strconv.Itoa(toLine))
+
+	result := strings.Builder{}
+
+	for lineNumber := fromLine; lineNumber <= toLine; lineNumber++ {
+		marker := " "
+		if lineNumber == line {
+			marker = ">"
+		}
+
+		lineContent := strings.TrimRight(lines[lineNumber-firstLine], "\r")
+
+		_, _ = fmt.Fprintf(&result, "%s %*d | %s\n", marker, numberWidth, lineNumber, lineContent)
+
+		if lineNumber == line && column > 0 {
+			_, _ = fmt.Fprintf(&result, "  %*s | %s^\n", numberWidth, "", strings.Repeat(" ", column-1))
+		}
+	}
+
+	return strings.TrimRight(result.String(), "\n")
+}
@@ -19,11 +19,11 @@ func (s *Impl) loadAggDicts(ctx context.Context) error {
 
 	aggDicts = append(aggDicts, s.siteConfig.AggDicts()...)
 
-	for _, extension := range s.extensions {
+	for _, extension := range s.sortedExtensions() {
 		aggDicts = append(aggDicts, extension.Config().AggDicts()...)
 	}
 
-	for _, theme := range s.themes {
+	for _, theme := range s.sortedThemes() {
 		aggDicts = append(aggDicts, theme.Config().AggDicts()...)
 	}
 
@@ -58,7 +58,7 @@ func (s *Impl) loadAggDict(ctx context.Context, aggDictConfig SiteAggDictConfig)
 func (s *Impl) loadAggDictsData(ctx context.Context) error {
 	s.log.GetLogger(ctx).Info("Loading agg dicts data...")
 
-	for _, aggDictConfig := range s.aggDicts {
+	for _, aggDictConfig := range s.sortedAggDicts() {
 		if err := s.loadAggDictData(ctx, aggDictConfig); err != nil {
 			return fmt.Errorf("%w: %s: %w", ErrLoadAggDictData, aggDictConfig.Name(), err)
 		}
@@ -83,7 +83,11 @@ func (s *Impl) loadAggDictData(ctx context.Context, aggDictConfig SiteAggDictCon
 
 		aggDictKeyData := make(map[string][]Page)
 
-		for _, page := range s.pages {
+		for _, page := range s.sortedPages() {
+			if !s.isListedPage(page) {
+				continue
+			}
+
 			pageConfig := page.Config()
 			pageVariables := pageConfig.Variables()
 
@@ -110,13 +114,27 @@ func (s *Impl) loadAggDictData(ctx context.Context, aggDictConfig SiteAggDictCon
 						aggDictKeyData[arrayValue] = append(aggDictKeyData[arrayValue], page)
 
 					default:
-						log.Warnf("Unsupported variable array value type: %T (%#v)", arrayValue, arrayValue)
+						s.warnf(
+							ctx,
+							page.FileInfo().Filename,
+							"Unsupported variable '%s' array value type: %T (%#v)",
+							aggDictKey,
+							arrayValue,
+							arrayValue,
+						)
 						// skip
 					}
 				}
 
 			default:
-				log.Warnf("Unsupported variable type: %T (%#v)", variable, variable)
+				s.warnf(
+					ctx,
+					page.FileInfo().Filename,
+					"Unsupported variable '%s' type: %T (%#v)",
+					aggDictKey,
+					variable,
+					variable,
+				)
 				// skip
 			}
 		}
@@ -0,0 +1,203 @@
+package stagen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+)
+
+const (
+	pageAliasesVariable      = "aliases"
+	netlifyRedirectsFilename = "_redirects"
+	nginxRedirectsFilename   = "redirects.map"
+)
+
+var ErrInvalidAlias = errors.New("invalid alias")
+
+type PageAlias struct {
+	Path     string
+	Filename string
+	PageId   string
+	PageUri  string
+}
+
+func (s *Impl) loadAliases(ctx context.Context) error {
+	s.log.GetLogger(ctx).Info("Loading aliases...")
+
+	pagesFilenames := s.pagesFilenames()
+
+	for _, page := range s.sortedPages() {
+		aliases, err := pageAliases(page)
+		if err == nil {
+			for _, alias := range aliases {
+				if err = s.addAlias(page, alias, pagesFilenames); err != nil {
+					break
+				}
+			}
+		}
+
+		if err != nil {
+			err = fmt.Errorf("%w: %s: %w", ErrLoadAlias, page.Id(), err)
+
+			if err = s.collectProblem(page.FileInfo().Filename, err); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// pagesFilenames maps the build filenames of the pages to their ids
+func (s *Impl) pagesFilenames() map[string]string {
+	pagesFilenames := make(map[string]string, len(s.pages))
+
+	for _, page := range s.pages {
+		pagesFilenames[s.pageBuildFilename(page.FileInfo())] = page.Id()
+	}
+
+	return pagesFilenames
+}
+
+func pageAliases(page Page) ([]string, error) {
+	switch aliasesValue := page.Config().Variables()[pageAliasesVariable].(type) {
+	case nil:
+		return nil, nil
+
+	case string:
+		return []string{aliasesValue}, nil
+
+	case []any:
+		aliases := make([]string, 0, len(aliasesValue))
+
+		for _, aliasValue := range aliasesValue {
+			alias, ok := aliasValue.(string)
+			if !ok || alias == "" {
+				return nil, fmt.Errorf("%w: %T (%#v)", ErrInvalidAlias, aliasValue, aliasValue)
+			}
+
+			aliases = append(aliases, alias)
+		}
+
+		return aliases, nil
+
+	default:
+		return nil, fmt.Errorf("%w: '%s' must be a list, got %T", ErrInvalidAlias, pageAliasesVariable, aliasesValue)
+	}
+}
+
+func (s *Impl) addAlias(page Page, alias string, pagesFilenames map[string]string) error {
+	aliasPath := path.Clean("/" + alias)
+	if strings.HasSuffix(alias, "/") && aliasPath != "/" {
+		aliasPath += "/"
+	}
+
+	_, aliasFilename := s.uriPaths(aliasPath, htmlExtension)
+
+	aliasId := strings.TrimSuffix(aliasFilename, path.Ext(aliasFilename))
+	if aliasId == "index" {
+		aliasId = ""
+	}
+
+	if _, ok := s.pages[aliasId]; ok {
+		return fmt.Errorf("%w: alias '%s'", ErrPageAlreadyExists, aliasId)
+	}
+
+	if pageId, ok := pagesFilenames[aliasFilename]; ok {
+		return fmt.Errorf("%w: alias '%s' is built by page '%s'", ErrPageAlreadyExists, alias, pageId)
+	}
+
+	if existingAlias, ok := s.aliases[aliasFilename]; ok {
+		return fmt.Errorf("%w: alias '%s' is also an alias of page '%s'", ErrPageAlreadyExists, alias, existingAlias.PageId)
+	}
+
+	s.aliases[aliasFilename] = &PageAlias{
+		Path:     aliasPath,
+		Filename: aliasFilename,
+		PageId:   page.Id(),
+		PageUri:  page.Uri(),
+	}
+
+	return nil
+}
+
+func (s *Impl) sortedAliases() []*PageAlias {
+	return slices.SortedFunc(maps.Values(s.aliases), func(a, b *PageAlias) int {
+		return strings.Compare(a.Filename, b.Filename)
+	})
+}
+
+func (s *Impl) buildAliases(ctx context.Context) error {
+	aliases := s.sortedAliases()
+
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	s.log.GetLogger(ctx).Infof("Building %d aliases...", len(aliases))
+
+	for _, alias := range aliases {
+		pageUrl, err := url.JoinPath(s.siteConfig.BaseUrl(), alias.PageUri)
+		if err != nil {
+			return fmt.Errorf("failed to resolve page '%s' url: %w", alias.PageId, err)
+		}
+
+		if err = s.saveBuildFile(ctx, alias.Filename, aliasRedirectPage(pageUrl)); err != nil {
+			return fmt.Errorf("failed to save alias '%s': %w", alias.Path, err)
+		}
+	}
+
+	if s.config.Settings().RedirectsNetlify() {
+		if err := s.saveBuildFile(ctx, netlifyRedirectsFilename, redirectsFile(aliases, "%s %s 301\n")); err != nil {
+			return fmt.Errorf("failed to save netlify redirects: %w", err)
+		}
+	}
+
+	if s.config.Settings().RedirectsNginx() {
+		if err := s.saveBuildFile(ctx, nginxRedirectsFilename, redirectsFile(aliases, "%s %s;\n")); err != nil {
+			return fmt.Errorf("failed to save nginx redirects: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func aliasRedirectPage(pageUrl string) []byte {
+	escapedUrl := html.EscapeString(pageUrl)
+
+	return fmt.Appendf(
+		nil,
+		`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%[1]s</title>
+<link rel="canonical" href="%[1]s">
+<meta name="robots" content="noindex">
+<meta http-equiv="refresh" content="0; url=%[1]s">
+</head>
+<body>
+<a href="%[1]s">%[1]s</a>
+</body>
+</html>
+`,
+		escapedUrl,
+	)
+}
+
+func redirectsFile(aliases []*PageAlias, lineFormat string) []byte {
+	result := bytes.Buffer{}
+
+	for _, alias := range aliases {
+		_, _ = fmt.Fprintf(&result, lineFormat, alias.Path, alias.PageUri)
+	}
+
+	return result.Bytes()
+}
@@ -3,42 +3,98 @@ package stagen
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"path/filepath"
-	"sort"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/pixality-inc/golang-core/timetrack"
 	"github.com/pixality-inc/golang-core/util"
+
+	"github.com/stagens/stagen/pkg/template_engine"
 )
 
+const defaultBuildDir = "build"
+
 type PageRenderConfig struct {
-	Page    Page
-	Theme   Theme
-	Data    map[string]any
-	Content []byte
+	Page          Page
+	Theme         Theme
+	Data          map[string]any
+	Content       []byte
+	ContentResult *ThemeRenderResult
 }
 
 func (s *Impl) Build(ctx context.Context) error {
+	track := timetrack.New(ctx)
+
 	if err := s.init(ctx); err != nil {
-		return fmt.Errorf("failed to initialize: %w", err)
+		return s.failBuild(ctx, track.Finish(), fmt.Errorf("failed to initialize: %w", err))
 	}
 
 	log := s.log.GetLogger(ctx)
 
 	log.Info("Running build...")
 
-	track := timetrack.New(ctx)
+	previousBuildManifest, err := s.loadBuildManifest(ctx)
+	if err != nil {
+		s.warnf(ctx, s.buildManifestFilename(), "Failed to load build manifest, stale files will not be removed: %v", err)
+
+		previousBuildManifest = NewBuildManifest(s.buildDir())
+	}
+
+	s.previousBuildManifest = previousBuildManifest
+	s.buildManifest = NewBuildManifest(s.buildDir())
+	s.report.ResetOutput()
+
+	if err = s.inStaging(ctx, func() error { return s.buildStaging(ctx) }); err != nil {
+		return s.failBuild(ctx, track.Finish(), err)
+	}
 
+	if err = s.saveBuildManifest(ctx); err != nil {
+		return s.failBuild(ctx, track.Finish(), fmt.Errorf("failed to save build manifest: %w", err))
+	}
+
+	duration := track.Finish()
+
+	if s.config.Settings().BuildReport() != "" {
+		if err = s.saveBuildReport(ctx, duration, nil); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Build finished in %s", util.FormatDuration(duration))
+
+	return nil
+}
+
+func (s *Impl) buildStaging(ctx context.Context) error {
 	if err := s.build(ctx); err != nil {
 		return fmt.Errorf("failed to build: %w", err)
 	}
 
+	if err := s.buildAliases(ctx); err != nil {
+		return fmt.Errorf("failed to build aliases: %w", err)
+	}
+
+	if err := s.copyPagesFiles(ctx); err != nil {
+		return fmt.Errorf("failed to copy pages files: %w", err)
+	}
+
 	if err := s.copyPublicFiles(ctx); err != nil {
 		return fmt.Errorf("failed to copy public files: %w", err)
 	}
 
-	log.Infof("Build finished in %s", util.FormatDuration(track.Finish()))
+	if err := s.carryOverForeignFiles(ctx); err != nil {
+		return fmt.Errorf("failed to keep foreign files: %w", err)
+	}
+
+	if err := s.strictError(); err != nil {
+		return fmt.Errorf("build has warnings: %w", err)
+	}
 
 	return nil
 }
@@ -54,31 +110,61 @@ func (s *Impl) getBasePageConfig() PageConfig {
 		false,
 		false,
 		false,
+		"",
 		templateConfig.Variables(),
 		templateConfig.Imports(),
 		templateConfig.Includes(),
 		templateConfig.Extras(),
 	)
 
-	extensions := make([]Extension, 0, len(s.extensions))
+	for _, extension := range s.sortedExtensions() {
+		pageConfig = MergePageConfigs(pageConfig, extension.Config().ToPageConfig())
+	}
+
+	return pageConfig
+}
 
-	for _, extension := range s.extensions {
-		extensions = append(extensions, extension)
+func (s *Impl) buildDir() string {
+	buildDir := s.config.Settings().BuildDir()
+	if buildDir == "" {
+		buildDir = defaultBuildDir
 	}
 
-	sort.Slice(extensions, func(i, j int) bool {
-		return extensions[i].Index() < extensions[j].Index()
-	})
+	return s.workDirPath(buildDir)
+}
 
-	for _, extension := range extensions {
-		pageConfig = MergePageConfigs(pageConfig, extension.Config().ToPageConfig())
+func (s *Impl) workDirPath(filename string) string {
+	if filepath.IsAbs(filename) {
+		// storage is rooted at the work dir, so absolute paths must be made relative to it
+		realWorkDir, err := filepath.Abs(s.realWorkDir)
+		if err == nil {
+			if relFilename, err := filepath.Rel(realWorkDir, filename); err == nil {
+				return filepath.Join(s.workDir, relFilename)
+			}
+		}
 	}
 
-	return pageConfig
+	return filepath.Join(s.workDir, filename)
 }
 
-func (s *Impl) buildDir() string {
-	return filepath.Join(s.workDir, "build")
+// siteConfigFilename is the site config file relative to the work dir, as the problems files are
+func (s *Impl) siteConfigFilename() string {
+	realWorkDir, err := filepath.Abs(s.realWorkDir)
+	if err != nil {
+		return s.configFile
+	}
+
+	configFile, err := filepath.Abs(s.configFile)
+	if err != nil {
+		return s.configFile
+	}
+
+	relFilename, err := filepath.Rel(realWorkDir, configFile)
+	if err != nil || strings.HasPrefix(relFilename, "..") {
+		return s.configFile
+	}
+
+	return relFilename
 }
 
 func (s *Impl) build(ctx context.Context) error {
@@ -86,13 +172,62 @@ func (s *Impl) build(ctx context.Context) error {
 
 	log.Info("Building pages...")
 
-	for _, page := range s.pages {
+	return s.buildPages(ctx, s.sortedPages())
+}
+
+func (s *Impl) buildPages(ctx context.Context, pages []Page) error {
+	return s.forEachPage(ctx, pages, func(page Page) error {
 		if err := s.buildPage(ctx, page); err != nil {
 			return fmt.Errorf("failed to build page '%s': %w", page.Id(), err)
 		}
+
+		return nil
+	})
+}
+
+func (s *Impl) forEachPage(ctx context.Context, pages []Page, fn func(page Page) error) error {
+	pages = sortPages(pages)
+
+	errs := make([]error, len(pages))
+
+	pagesIndexes := make(chan int)
+
+	wg := sync.WaitGroup{}
+
+	for range min(s.jobs(), len(pages)) {
+		wg.Go(func() {
+			for index := range pagesIndexes {
+				page := pages[index]
+
+				if err := ctx.Err(); err != nil {
+					errs[index] = fmt.Errorf("failed to build page '%s': %w", page.Id(), err)
+
+					continue
+				}
+
+				errs[index] = fn(page)
+			}
+		})
 	}
 
-	return nil
+	for index := range pages {
+		pagesIndexes <- index
+	}
+
+	close(pagesIndexes)
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (s *Impl) jobs() int {
+	jobs := s.config.Settings().Jobs()
+	if jobs <= 0 {
+		jobs = runtime.GOMAXPROCS(0)
+	}
+
+	return jobs
 }
 
 func (s *Impl) buildPage(ctx context.Context, page Page) error {
@@ -116,17 +251,45 @@ func (s *Impl) buildPage(ctx context.Context, page Page) error {
 		pageConfig.IsDraft(),
 	)
 
-	renderedContent, err := s.renderPage(ctx, pageRenderConfig)
+	renderStartedAt := time.Now()
+
+	renderResult, err := s.renderPage(ctx, pageRenderConfig)
 	if err != nil {
 		return fmt.Errorf("failed to render page '%s': %w", pageId, err)
 	}
 
+	renderDuration := time.Since(renderStartedAt)
+
 	pageFileInfo := page.FileInfo()
 
-	if err = s.saveBuildPage(ctx, pageFileInfo, renderedContent); err != nil {
+	if pageContent, ok := s.pagesContents[page.Id()]; ok && pageContent.Err != nil {
+		s.warnf(ctx, pageFileInfo.Filename, "Page '%s' has no summary, toc and word count, its content failed to render without them: %v", pageId, pageContent.Err)
+	}
+
+	if err = s.saveBuildPage(ctx, pageFileInfo, renderResult.Content); err != nil {
 		return fmt.Errorf("failed to save page '%s': %w", pageId, err)
 	}
 
+	s.report.AddPage(BuildReportPage{
+		Id:               page.Id(),
+		Source:           pageFileInfo.Filename,
+		Theme:            pageRenderConfig.Theme.Name(),
+		Layout:           pageConfig.Layout(),
+		Output:           s.pageBuildFilename(pageFileInfo),
+		Bytes:            len(renderResult.Content),
+		RenderDurationMs: durationMs(renderDuration),
+	})
+
+	dependencies := make([]string, 0, len(renderResult.Dependencies)+len(renderResult.DataKeys))
+
+	dependencies = append(dependencies, renderResult.Dependencies...)
+
+	for _, dataKey := range renderResult.DataKeys {
+		dependencies = append(dependencies, dataDependency(dataKey))
+	}
+
+	s.dependencies.Set(page.Id(), dependencies)
+
 	return nil
 }
 
@@ -152,85 +315,68 @@ func (s *Impl) getPageRenderConfig(
 
 	pageContent := page.Content()
 
+	var contentResult *ThemeRenderResult
+
+	if pageContentResult, ok := s.pagesContents[pageId]; ok {
+		contentResult = pageContentResult.Result
+	}
+
 	pageRenderConfig := &PageRenderConfig{
-		Page:    page,
-		Theme:   theme,
-		Data:    data,
-		Content: pageContent,
+		Page:          page,
+		Theme:         theme,
+		Data:          data,
+		Content:       pageContent,
+		ContentResult: contentResult,
 	}
 
 	return pageRenderConfig, nil
 }
 
-func (s *Impl) renderPage(ctx context.Context, pageRenderConfig *PageRenderConfig) ([]byte, error) {
+func (s *Impl) renderPage(ctx context.Context, pageRenderConfig *PageRenderConfig) (*ThemeRenderResult, error) {
 	pageId := pageRenderConfig.Page.Id()
 	pageConfig := pageRenderConfig.Page.Config()
 
-	renderedContent, err := pageRenderConfig.Theme.Render(
+	pageFileInfo := pageRenderConfig.Page.FileInfo()
+
+	renderResult, err := pageRenderConfig.Theme.Render(
 		ctx,
 		pageConfig.Imports(),
 		pageConfig.Layout(),
-		pageRenderConfig.Content,
-		pageRenderConfig.Page.FileInfo().IsMarkdown,
+		template_engine.Source{
+			File:    pageFileInfo.SourceFilename,
+			Line:    pageFileInfo.ContentLine,
+			Content: string(pageRenderConfig.Content),
+		},
+		pageFileInfo.IsMarkdown,
 		pageRenderConfig.Data,
+		pageRenderConfig.ContentResult,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to render page '%s': %w", pageId, err)
 	}
 
-	return renderedContent, nil
+	return renderResult, nil
 }
 
 func (s *Impl) getTemplateData(
 	_ context.Context,
 	page Page,
 ) (map[string]any, error) {
-	pageData := func(pageEntry Page) (map[string]any, error) {
-		pageId := pageEntry.Id()
-		pageConfig := pageEntry.Config()
-		pageFileInfo := pageEntry.FileInfo()
-
-		pageUrl, err := url.JoinPath(s.siteConfig.BaseUrl(), pageEntry.Uri())
-		if err != nil {
-			return nil, fmt.Errorf("failed to resolve page '%s' url: %w", pageId, err)
-		}
-
-		data := map[string]any{
-			"Id":         pageId,
-			"Name":       pageEntry.Name(),
-			"Uri":        pageEntry.Uri(),
-			"Url":        pageUrl,
-			"Title":      pageConfig.Title(),
-			"IsHidden":   pageConfig.IsHidden(),
-			"IsDraft":    pageConfig.IsDraft(),
-			"IsSystem":   pageConfig.IsSystem(),
-			"CreatedAt":  pageFileInfo.CreatedAt,
-			"ModifiedAt": pageFileInfo.ModifiedAt,
-			"AccessedAt": pageFileInfo.AccessedAt,
-			"ChangedAt":  pageFileInfo.ChangedAt,
-			"Variables":  pageConfig.Variables(),
-			"Imports":    pageConfig.Imports(),
-			"Includes":   pageConfig.Includes(),
-			"Extras":     pageConfig.Extras(),
-		}
-
-		return data, nil
+	pagesData, err := s.templatePagesData()
+	if err != nil {
+		return nil, err
 	}
 
-	pagesData := make(map[string]any)
-
-	for _, pageEntry := range s.pages {
-		pageEntryData, err := pageData(pageEntry)
+	pageEntryData, ok := pagesData[page.Id()]
+	if !ok {
+		unlistedPageData, err := s.pageData(page)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get page entry data for page '%s': %w", pageEntry.Id(), err)
+			return nil, fmt.Errorf("failed to get page entry data for page '%s': %w", page.Id(), err)
 		}
 
-		pagesData[pageEntry.Id()] = pageEntryData
-	}
+		unlistedPageData["Paginator"] = s.paginatorData(page.Id(), pagesData)
 
-	pageEntryData, err := pageData(page)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get page entry data for page '%s': %w", page.Id(), err)
+		pageEntryData = unlistedPageData
 	}
 
 	data := map[string]any{
@@ -240,9 +386,13 @@ func (s *Impl) getTemplateData(
 			"Author":    s.siteConfig.Author(),
 			"Copyright": s.siteConfig.Copyright(),
 			"Logo":      s.siteConfig.Logo(),
+			"Sections":  s.siteSections(),
+			"Lang":      s.defaultLanguage().Code,
+			"Languages": s.languages,
 		},
 		"Page": pageEntryData,
 		"System": map[string]any{
+			"Env":       s.config.Env(),
 			"BuildTime": s.buildTime,
 			"Now":       s.clock.Now(),
 		},
@@ -250,6 +400,7 @@ func (s *Impl) getTemplateData(
 		"Databases":    s.databases,
 		"AggDicts":     s.aggDicts,
 		"AggDictsData": s.aggDictsData,
+		"I18n":         s.pageI18n(page),
 	}
 
 	for k, v := range page.Config().Variables() {
@@ -259,20 +410,151 @@ func (s *Impl) getTemplateData(
 	return data, nil
 }
 
+// templatePagesData is the Pages of the template data, it's built once and shared by all the pages
+func (s *Impl) templatePagesData() (map[string]any, error) {
+	s.pagesDataMutex.Lock()
+	defer s.pagesDataMutex.Unlock()
+
+	if s.pagesData != nil {
+		return s.pagesData, nil
+	}
+
+	pagesData := make(map[string]any)
+
+	for _, pageEntry := range s.pages {
+		if !s.isListedPage(pageEntry) {
+			continue
+		}
+
+		pageEntryData, err := s.pageData(pageEntry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get page entry data for page '%s': %w", pageEntry.Id(), err)
+		}
+
+		pagesData[pageEntry.Id()] = pageEntryData
+	}
+
+	// the paginators refer to the other pages, so they go in when all of them are there
+	for pageId := range s.paginators {
+		if pageEntryData, ok := pagesData[pageId].(map[string]any); ok {
+			pageEntryData["Paginator"] = s.paginatorData(pageId, pagesData)
+		}
+	}
+
+	s.pagesData = pagesData
+
+	return pagesData, nil
+}
+
+// resetPagesData drops the shared Pages data, it's built again with the next template data
+func (s *Impl) resetPagesData() {
+	s.pagesDataMutex.Lock()
+	defer s.pagesDataMutex.Unlock()
+
+	s.pagesData = nil
+}
+
+func (s *Impl) pageData(page Page) (map[string]any, error) {
+	pageId := page.Id()
+	pageConfig := page.Config()
+	pageFileInfo := page.FileInfo()
+
+	pageUrl, err := url.JoinPath(s.siteConfig.BaseUrl(), page.Uri())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve page '%s' url: %w", pageId, err)
+	}
+
+	pageText := s.pageText(pageId)
+	pageRelations := s.pageRelations(page)
+
+	data := map[string]any{
+		"Id":               pageId,
+		"Name":             page.Name(),
+		"Uri":              page.Uri(),
+		"Url":              pageUrl,
+		"Title":            pageConfig.Title(),
+		"IsHidden":         pageConfig.IsHidden(),
+		"IsDraft":          pageConfig.IsDraft(),
+		"IsSystem":         pageConfig.IsSystem(),
+		"ShowDraftMarker":  pageConfig.IsDraft() && s.config.Env() == EnvDev,
+		"CreatedAt":        pageFileInfo.CreatedAt,
+		"ModifiedAt":       pageFileInfo.ModifiedAt,
+		"AccessedAt":       pageFileInfo.AccessedAt,
+		"ChangedAt":        pageFileInfo.ChangedAt,
+		"Git":              s.pageGitData(page),
+		"Variables":        pageConfig.Variables(),
+		"Imports":          pageConfig.Imports(),
+		"Includes":         pageConfig.Includes(),
+		"Extras":           pageConfig.Extras(),
+		"Resources":        pageFileInfo.Resources,
+		"Summary":          pageText.Summary,
+		"Plain":            pageText.Plain,
+		"WordCount":        pageText.WordCount,
+		"ReadingTime":      pageText.ReadingTime,
+		"Toc":              pageText.Toc,
+		"Section":          pageRelations.Section,
+		"Parent":           pageRelations.Parent,
+		"HasParent":        pageRelations.HasParent,
+		"Children":         pageRelations.Children,
+		"Ancestors":        pageRelations.Ancestors,
+		"Prev":             pageRelations.Prev,
+		"HasPrev":          pageRelations.HasPrev,
+		"Next":             pageRelations.Next,
+		"HasNext":          pageRelations.HasNext,
+		"PrevInSection":    pageRelations.PrevInSection,
+		"HasPrevInSection": pageRelations.HasPrevInSection,
+		"NextInSection":    pageRelations.NextInSection,
+		"HasNextInSection": pageRelations.HasNextInSection,
+		"Paginator":        s.paginators[pageId],
+		"Lang":             pageFileInfo.Lang,
+		"Translations":     s.pageTranslations(pageId),
+		"Alternates":       s.pageAlternates(pageId),
+	}
+
+	return data, nil
+}
+
+func (s *Impl) pageBuildFilename(pageFileInfo *PageFileInfo) string {
+	if pageFileInfo.OutputFilename != "" {
+		return pageFileInfo.OutputFilename
+	}
+
+	fileExt := pageFileInfo.FileExtension
+	if pageFileInfo.IsMarkdown {
+		fileExt = ".html"
+	}
+
+	return filepath.Join(pageFileInfo.PathWithoutWorkDirAndPagesDir, pageFileInfo.FilenameWithoutExtension) + fileExt
+}
+
 func (s *Impl) saveBuildPage(
 	ctx context.Context,
 	pageFileInfo *PageFileInfo,
 	content []byte,
+) error {
+	return s.saveBuildFile(ctx, s.pageBuildFilename(pageFileInfo), content)
+}
+
+func (s *Impl) saveBuildFile(
+	ctx context.Context,
+	filename string,
+	content []byte,
 ) error {
 	log := s.log.GetLogger(ctx)
 
-	fileExt := pageFileInfo.FileExtension
-	if pageFileInfo.IsMarkdown {
-		fileExt = ".html"
-	}
+	saveFilename := filepath.Join(s.outputDir(), filename)
+
+	hash := contentHash(content)
+
+	s.buildManifest.Set(filename, hash)
 
-	filename := filepath.Join(pageFileInfo.PathWithoutWorkDirAndPagesDir, pageFileInfo.FilenameWithoutExtension) + fileExt
-	saveFilename := filepath.Join(s.buildDir(), filename)
+	if unchanged, err := s.reuseBuildFile(ctx, filename, hash); err != nil {
+		return err
+	} else if unchanged {
+		log.Debugf("File %s is unchanged", saveFilename)
+
+		return nil
+	}
 
 	log.Debugf(
 		"Saving %d bytes to %s...",
@@ -280,15 +562,8 @@ func (s *Impl) saveBuildPage(
 		saveFilename,
 	)
 
-	dir := filepath.Dir(saveFilename)
-	if _, ok := s.createdDirs[dir]; !ok {
-		log.Debugf("Creating directory '%s'...", dir)
-
-		if err := s.storage.MkDir(ctx, dir); err != nil {
-			return fmt.Errorf("failed to create directory '%s': %w", dir, err)
-		}
-
-		s.createdDirs[dir] = struct{}{}
+	if err := s.createBuildDir(ctx, filepath.Dir(saveFilename)); err != nil {
+		return err
 	}
 
 	if err := s.storage.Write(ctx, saveFilename, bytes.NewReader(content)); err != nil {
@@ -297,3 +572,22 @@ func (s *Impl) saveBuildPage(
 
 	return nil
 }
+
+func (s *Impl) createBuildDir(ctx context.Context, dir string) error {
+	s.dirsMutex.Lock()
+	defer s.dirsMutex.Unlock()
+
+	if _, ok := s.createdDirs[dir]; ok {
+		return nil
+	}
+
+	s.log.GetLogger(ctx).Debugf("Creating directory '%s'...", dir)
+
+	if err := s.storage.MkDir(ctx, dir); err != nil {
+		return fmt.Errorf("failed to create directory '%s': %w", dir, err)
+	}
+
+	s.createdDirs[dir] = struct{}{}
+
+	return nil
+}
@@ -0,0 +1,290 @@
+package stagen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pixality-inc/golang-core/json"
+)
+
+var ErrStrict = errors.New("strict mode")
+
+type BuildReportStatus string
+
+const (
+	BuildReportStatusSuccess BuildReportStatus = "success"
+	BuildReportStatusFailed  BuildReportStatus = "failed"
+)
+
+type BuildReportPage struct {
+	Id               string  `json:"id"`
+	Source           string  `json:"source"`
+	Theme            string  `json:"theme"`
+	Layout           string  `json:"layout"`
+	Output           string  `json:"output"`
+	Bytes            int     `json:"bytes"`
+	RenderDurationMs float64 `json:"render_duration_ms"`
+}
+
+type BuildReportGenerator struct {
+	Name    string `json:"name"`
+	Pages   int    `json:"pages"`
+	Skipped int    `json:"skipped"`
+}
+
+type BuildReportPublicFile struct {
+	Source string `json:"source"`
+	Output string `json:"output"`
+}
+
+type BuildReportWarning struct {
+	File    string `json:"file,omitempty"`
+	Message string `json:"message"`
+}
+
+func (w BuildReportWarning) String() string {
+	if w.File == "" {
+		return w.Message
+	}
+
+	return w.File + ": " + w.Message
+}
+
+type BuildReport struct {
+	Version     string                  `json:"version"`
+	Env         string                  `json:"env"`
+	BuildTime   time.Time               `json:"build_time"`
+	DurationMs  float64                 `json:"duration_ms"`
+	Status      BuildReportStatus       `json:"status"`
+	Error       string                  `json:"error,omitempty"`
+	Pages       []BuildReportPage       `json:"pages"`
+	Generators  []BuildReportGenerator  `json:"generators"`
+	PublicFiles []BuildReportPublicFile `json:"public_files"`
+	Warnings    []BuildReportWarning    `json:"warnings"`
+	pages       map[string]BuildReportPage
+	publicFiles map[string]BuildReportPublicFile
+	mutex       sync.Mutex
+}
+
+func NewBuildReport() *BuildReport {
+	return &BuildReport{
+		Version:     Version,
+		Env:         "",
+		BuildTime:   time.Time{},
+		DurationMs:  0,
+		Status:      BuildReportStatusSuccess,
+		Error:       "",
+		Pages:       make([]BuildReportPage, 0),
+		Generators:  make([]BuildReportGenerator, 0),
+		PublicFiles: make([]BuildReportPublicFile, 0),
+		Warnings:    make([]BuildReportWarning, 0),
+		pages:       make(map[string]BuildReportPage),
+		publicFiles: make(map[string]BuildReportPublicFile),
+		mutex:       sync.Mutex{},
+	}
+}
+
+// AddPage is keyed by the output, an incremental rebuild renders the page again over its previous entry
+func (r *BuildReport) AddPage(page BuildReportPage) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.pages[page.Output] = page
+}
+
+func (r *BuildReport) AddGenerator(generator BuildReportGenerator) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// generators are built twice, so the last pass replaces the previous one
+	index := slices.IndexFunc(r.Generators, func(existing BuildReportGenerator) bool {
+		return existing.Name == generator.Name
+	})
+
+	if index >= 0 {
+		r.Generators[index] = generator
+
+		return
+	}
+
+	r.Generators = append(r.Generators, generator)
+}
+
+func (r *BuildReport) AddPublicFile(publicFile BuildReportPublicFile) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.publicFiles[publicFile.Output] = publicFile
+}
+
+func (r *BuildReport) AddWarning(warning BuildReportWarning) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// agg dicts and generators are loaded twice, so the same warning may come again
+	if slices.Contains(r.Warnings, warning) {
+		return false
+	}
+
+	r.Warnings = append(r.Warnings, warning)
+
+	return true
+}
+
+func (r *BuildReport) CollectedWarnings() []BuildReportWarning {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return slices.Clone(r.Warnings)
+}
+
+// KeepOutput takes the pages and public files of the previous report for an incremental rebuild
+func (r *BuildReport) KeepOutput(previous *BuildReport) {
+	previous.mutex.Lock()
+	pages := maps.Clone(previous.pages)
+	publicFiles := maps.Clone(previous.publicFiles)
+	previous.mutex.Unlock()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.pages = pages
+	r.publicFiles = publicFiles
+}
+
+func (r *BuildReport) RemoveOutput(output string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.pages, output)
+	delete(r.publicFiles, output)
+}
+
+func (r *BuildReport) ResetOutput() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.pages = make(map[string]BuildReportPage)
+	r.publicFiles = make(map[string]BuildReportPublicFile)
+}
+
+func (r *BuildReport) Marshal() ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// pages are rendered in parallel, so the order is fixed here
+	r.Pages = make([]BuildReportPage, 0, len(r.pages))
+
+	for _, output := range slices.Sorted(maps.Keys(r.pages)) {
+		r.Pages = append(r.Pages, r.pages[output])
+	}
+
+	slices.SortFunc(r.Generators, func(a, b BuildReportGenerator) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	r.PublicFiles = make([]BuildReportPublicFile, 0, len(r.publicFiles))
+
+	for _, output := range slices.Sorted(maps.Keys(r.publicFiles)) {
+		r.PublicFiles = append(r.PublicFiles, r.publicFiles[output])
+	}
+
+	return json.Marshal(r)
+}
+
+func (s *Impl) warnf(ctx context.Context, file string, format string, args ...any) {
+	warning := BuildReportWarning{
+		File:    file,
+		Message: fmt.Sprintf(format, args...),
+	}
+
+	if !s.report.AddWarning(warning) {
+		return
+	}
+
+	s.log.GetLogger(ctx).Warn(warning.String())
+
+	if s.check != nil {
+		s.check.Add(warning.File, warning.Message)
+	}
+}
+
+func (s *Impl) buildReportFilename() string {
+	return s.workDirPath(s.config.Settings().BuildReport())
+}
+
+// saveBuildReport writes the report of a build, a failed one gets the failed status and its error
+func (s *Impl) saveBuildReport(ctx context.Context, duration time.Duration, buildErr error) error {
+	reportFilename := s.buildReportFilename()
+
+	s.report.Env = s.config.Env()
+	s.report.BuildTime = s.buildTime
+	s.report.DurationMs = durationMs(duration)
+	s.report.Status = BuildReportStatusSuccess
+	s.report.Error = ""
+
+	if buildErr != nil {
+		s.report.Status = BuildReportStatusFailed
+		s.report.Error = buildErr.Error()
+	}
+
+	reportContent, err := s.report.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to marshal build report: %w", err)
+	}
+
+	if dir := filepath.Dir(reportFilename); dir != "." {
+		if err = s.storage.MkDir(ctx, dir); err != nil {
+			return fmt.Errorf("failed to create directory '%s': %w", dir, err)
+		}
+	}
+
+	if err = s.storage.Write(ctx, reportFilename, bytes.NewReader(reportContent)); err != nil {
+		return fmt.Errorf("failed to save build report %s: %w", reportFilename, err)
+	}
+
+	s.log.GetLogger(ctx).Infof("Build report saved to %s", reportFilename)
+
+	return nil
+}
+
+// failBuild saves the report of a failed build, CI needs its warnings the most, and returns the build error
+func (s *Impl) failBuild(ctx context.Context, duration time.Duration, buildErr error) error {
+	if s.config.Settings().BuildReport() == "" {
+		return buildErr
+	}
+
+	if err := s.saveBuildReport(ctx, duration, buildErr); err != nil {
+		s.log.GetLogger(ctx).WithError(err).Errorf("failed to save build report")
+	}
+
+	return buildErr
+}
+
+func (s *Impl) strictError() error {
+	if !s.config.Settings().Strict() {
+		return nil
+	}
+
+	warnings := s.report.CollectedWarnings()
+
+	errs := make([]error, 0, len(warnings))
+
+	for _, warning := range warnings {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrStrict, warning))
+	}
+
+	return errors.Join(errs...)
+}
+
+func durationMs(duration time.Duration) float64 {
+	return float64(duration.Microseconds()) / 1000
+}
@@ -0,0 +1,153 @@
+package stagen
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/stagens/stagen/pkg/source_error"
+	"github.com/stagens/stagen/pkg/template_engine"
+)
+
+type CheckProblem struct {
+	File    string
+	Message string
+}
+
+func (p CheckProblem) String() string {
+	if p.File == "" {
+		return p.Message
+	}
+
+	return p.File + ": " + p.Message
+}
+
+type CheckResult struct {
+	problems []CheckProblem
+	seen     map[CheckProblem]struct{}
+}
+
+func NewCheckResult() *CheckResult {
+	return &CheckResult{
+		problems: make([]CheckProblem, 0),
+		seen:     make(map[CheckProblem]struct{}),
+	}
+}
+
+func (r *CheckResult) Add(file string, message string) {
+	problem := CheckProblem{
+		File:    file,
+		Message: message,
+	}
+
+	// generators are loaded twice, so the same problem may be reported again
+	if _, ok := r.seen[problem]; ok {
+		return
+	}
+
+	r.seen[problem] = struct{}{}
+	r.problems = append(r.problems, problem)
+}
+
+func (r *CheckResult) Problems() []CheckProblem {
+	return slices.SortedStableFunc(slices.Values(r.problems), func(a, b CheckProblem) int {
+		return cmp.Compare(a.File, b.File)
+	})
+}
+
+func (s *Impl) Check(ctx context.Context) ([]CheckProblem, error) {
+	log := s.log.GetLogger(ctx)
+
+	log.Info("Checking project...")
+
+	s.check = NewCheckResult()
+
+	defer func() {
+		s.check = nil
+	}()
+
+	if err := s.init(ctx); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
+		// the loading stopped on a problem, which is in its source file or in the site config
+		file := s.siteConfigFilename()
+
+		var sourceErr *source_error.SourceError
+		if errors.As(err, &sourceErr) {
+			file = sourceErr.File
+		}
+
+		s.check.Add(file, err.Error())
+	} else {
+		s.checkPages(ctx)
+	}
+
+	problems := s.check.Problems()
+
+	log.Infof("Check finished, %d problem(s) found", len(problems))
+
+	return problems, nil
+}
+
+// collectProblem records err while checking a project so loading can go on, otherwise err is returned as is
+func (s *Impl) collectProblem(file string, err error) error {
+	if s.check == nil || err == nil {
+		return err
+	}
+
+	s.check.Add(file, err.Error())
+
+	return nil
+}
+
+func (s *Impl) checkPages(ctx context.Context) {
+	for _, pageId := range slices.Sorted(maps.Keys(s.pages)) {
+		page := s.pages[pageId]
+		pageFilename := page.FileInfo().Filename
+		pageConfig := page.Config()
+
+		theme, ok := s.themes[pageConfig.Theme()]
+		if !ok {
+			s.check.Add(pageFilename, fmt.Sprintf("%s: %s", ErrThemeNotFound, pageConfig.Theme()))
+
+			continue
+		}
+
+		s.checkTemplate(ctx, pageFilename, theme, template_engine.LoadTypeLayout, pageConfig.Layout())
+
+		for _, group := range slices.Sorted(maps.Keys(pageConfig.Imports())) {
+			for _, importValue := range pageConfig.Imports()[group] {
+				s.checkTemplate(ctx, pageFilename, theme, template_engine.LoadTypeImport, importValue.Name())
+			}
+		}
+
+		for _, group := range slices.Sorted(maps.Keys(pageConfig.Includes())) {
+			for _, includeValue := range pageConfig.Includes()[group] {
+				s.checkTemplate(ctx, pageFilename, theme, template_engine.LoadTypeInclude, includeValue.Name())
+			}
+		}
+	}
+}
+
+func (s *Impl) checkTemplate(
+	ctx context.Context,
+	pageFilename string,
+	theme Theme,
+	loadType template_engine.LoadType,
+	name string,
+) {
+	if _, err := theme.LoadTemplate(ctx, loadType, name); err != nil {
+		message := err.Error()
+
+		if errors.Is(err, template_engine.ErrTemplateNotFound) {
+			message = fmt.Sprintf("%s '%s' not found in theme '%s'", loadType, name, theme.Name())
+		}
+
+		s.check.Add(pageFilename, message)
+	}
+}
@@ -8,8 +8,40 @@ import (
 	"github.com/pixality-inc/golang-core/json"
 )
 
+const EnvDev = "dev"
+
+type PageTimeSource string
+
+const (
+	PageTimeSourceFs          PageTimeSource = "fs"
+	PageTimeSourceFrontMatter PageTimeSource = "front_matter"
+	PageTimeSourceClock       PageTimeSource = "clock"
+)
+
+type TrailingSlash string
+
+const (
+	TrailingSlashAlways TrailingSlash = "always"
+	TrailingSlashNever  TrailingSlash = "never"
+)
+
 type SettingsConfig interface {
 	UseUriHtmlFileExtension() bool
+	Jobs() int
+	PageTimeSource() PageTimeSource
+	GitInfo() bool
+	BuildDrafts() bool
+	BuildFuture() bool
+	BuildDir() string
+	BuildReport() string
+	Strict() bool
+	RedirectsNetlify() bool
+	RedirectsNginx() bool
+	PrettyUrls() bool
+	TrailingSlash() TrailingSlash
+	Passthrough() []string
+	TocMinLevel() int
+	TocMaxLevel() int
 }
 
 type Config interface {
@@ -42,6 +74,12 @@ type SiteExtensionConfig interface {
 	Name() string
 }
 
+type SiteLanguageConfig interface {
+	Code() string
+	Name() string
+	Prefix() string
+}
+
 // SiteAggDictConfig
 //
 //nolint:iface
@@ -138,6 +176,8 @@ type DirConfig interface {
 	Title() string
 	IsHidden() bool
 	IsDraft() bool
+	Permalink() string
+	Passthrough() []string
 	Variables() map[string]any
 	Imports() map[string][]SiteConfigTemplateImport
 	Includes() map[string][]SiteConfigTemplateInclude
@@ -154,12 +194,18 @@ type PageConfig interface {
 	IsHidden() bool
 	IsDraft() bool
 	IsSystem() bool
+	Permalink() string
 	Variables() map[string]any
 	Imports() map[string][]SiteConfigTemplateImport
 	Includes() map[string][]SiteConfigTemplateInclude
 	Extras() map[string][]SiteConfigTemplateExtra
 }
 
+type PaginateConfig interface {
+	Collection() string
+	Size() int
+}
+
 // SiteConfigTemplateImport
 //
 //nolint:iface
@@ -196,6 +242,7 @@ type SiteConfig interface {
 	Name() string
 	Description() string
 	Lang() string
+	Languages() []SiteLanguageConfig
 	Author() SiteConfigAuthor
 	Logo() SiteConfigLogo
 	Copyright() SiteConfigCopyright
@@ -213,6 +260,7 @@ type PageConfigImpl struct {
 	isHidden     bool
 	isDraft      bool
 	isSystem     bool
+	permalink    string
 	variables    map[string]any
 	imports      map[string][]SiteConfigTemplateImport
 	includes     map[string][]SiteConfigTemplateInclude
@@ -228,6 +276,7 @@ func NewDefaultPageConfig(configSource string, variables map[string]any) *PageCo
 		false,
 		false,
 		false,
+		"",
 		variables,
 		nil,
 		nil,
@@ -243,6 +292,7 @@ func NewPageConfig(
 	isHidden bool,
 	isDraft bool,
 	isSystem bool,
+	permalink string,
 	variables map[string]any,
 	imports map[string][]SiteConfigTemplateImport,
 	includes map[string][]SiteConfigTemplateInclude,
@@ -272,6 +322,7 @@ func NewPageConfig(
 		isHidden:     isHidden,
 		isDraft:      isDraft,
 		isSystem:     isSystem,
+		permalink:    permalink,
 		variables:    variables,
 		imports:      imports,
 		includes:     includes,
@@ -307,6 +358,10 @@ func (p *PageConfigImpl) IsSystem() bool {
 	return p.isSystem
 }
 
+func (p *PageConfigImpl) Permalink() string {
+	return p.permalink
+}
+
 func (p *PageConfigImpl) Variables() map[string]any {
 	return p.variables
 }
@@ -344,16 +399,21 @@ func MergePageConfigs(cfg1 PageConfig, cfg2 PageConfig) PageConfig {
 		isHidden = true
 	}
 
-	isDraft := cfg2.IsDraft()
+	isDraft := cfg1.IsDraft()
 	if cfg2.IsDraft() {
 		isDraft = true
 	}
 
-	isSystem := cfg2.IsSystem()
+	isSystem := cfg1.IsSystem()
 	if cfg2.IsSystem() {
 		isSystem = true
 	}
 
+	permalink := cfg1.Permalink()
+	if cfg2.Permalink() != "" {
+		permalink = cfg2.Permalink()
+	}
+
 	variables := cloneMap(cfg1.Variables())
 	for k, v := range cfg2.Variables() {
 		//nolint:modernize // @todo
@@ -383,6 +443,7 @@ func MergePageConfigs(cfg1 PageConfig, cfg2 PageConfig) PageConfig {
 		isHidden,
 		isDraft,
 		isSystem,
+		permalink,
 		variables,
 		imports,
 		includes,
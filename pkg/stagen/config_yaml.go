@@ -121,6 +121,24 @@ func (c *SiteExtensionConfigYaml) Name() string {
 	return c.NameValue
 }
 
+type SiteLanguageConfigYaml struct {
+	CodeValue   string `yaml:"code"`
+	NameValue   string `yaml:"name"`
+	PrefixValue string `yaml:"prefix"`
+}
+
+func (c *SiteLanguageConfigYaml) Code() string {
+	return c.CodeValue
+}
+
+func (c *SiteLanguageConfigYaml) Name() string {
+	return c.NameValue
+}
+
+func (c *SiteLanguageConfigYaml) Prefix() string {
+	return c.PrefixValue
+}
+
 type ExtensionConfigAuthorYaml struct {
 	NameValue    string `yaml:"name"`
 	EmailValue   string `yaml:"email"`
@@ -196,6 +214,7 @@ func (c *ExtensionConfigYaml) ToPageConfig() PageConfig {
 		false,
 		false,
 		false,
+		"",
 		c.Variables(),
 		c.Imports(),
 		c.Includes(),
@@ -371,6 +390,7 @@ func (c *ThemeConfigYaml) ToPageConfig() PageConfig {
 		false,
 		false,
 		false,
+		"",
 		c.Variables(),
 		c.Imports(),
 		c.Includes(),
@@ -379,13 +399,88 @@ func (c *ThemeConfigYaml) ToPageConfig() PageConfig {
 }
 
 type ConfigSettingsYaml struct {
-	UseUriHtmlFileExtensionValue bool `env:"USE_URI_HTML_FILE_EXTENSION" env-default:"true" yaml:"use_uri_html_file_extension"`
+	UseUriHtmlFileExtensionValue bool           `env:"USE_URI_HTML_FILE_EXTENSION" env-default:"true"   yaml:"use_uri_html_file_extension"`
+	JobsValue                    int            `env:"JOBS"                        env-default:"0"      yaml:"jobs"`
+	PageTimeSourceValue          PageTimeSource `env:"PAGE_TIME_SOURCE"            env-default:"fs"     yaml:"page_time_source"`
+	GitInfoValue                 bool           `env:"GIT_INFO"                    env-default:"true"   yaml:"git_info"`
+	BuildDraftsValue             bool           `env:"BUILD_DRAFTS"                env-default:"false"  yaml:"build_drafts"`
+	BuildFutureValue             bool           `env:"BUILD_FUTURE"                env-default:"false"  yaml:"build_future"`
+	BuildDirValue                string         `env:"BUILD_DIR"                   env-default:"build"  yaml:"build_dir"`
+	BuildReportValue             string         `env:"BUILD_REPORT"                env-default:""       yaml:"build_report"`
+	StrictValue                  bool           `env:"STRICT"                      env-default:"false"  yaml:"strict"`
+	RedirectsNetlifyValue        bool           `env:"REDIRECTS_NETLIFY"           env-default:"false"  yaml:"redirects_netlify"`
+	RedirectsNginxValue          bool           `env:"REDIRECTS_NGINX"             env-default:"false"  yaml:"redirects_nginx"`
+	PrettyUrlsValue              bool           `env:"PRETTY_URLS"                 env-default:"false"  yaml:"pretty_urls"`
+	TrailingSlashValue           TrailingSlash  `env:"TRAILING_SLASH"              env-default:"always" yaml:"trailing_slash"`
+	PassthroughValue             []string       `env:"PASSTHROUGH"                 env-default:""       yaml:"passthrough"`
+	TocMinLevelValue             int            `env:"TOC_MIN_LEVEL"               env-default:"2"      yaml:"toc_min_level"`
+	TocMaxLevelValue             int            `env:"TOC_MAX_LEVEL"               env-default:"3"      yaml:"toc_max_level"`
 }
 
 func (c *ConfigSettingsYaml) UseUriHtmlFileExtension() bool {
 	return c.UseUriHtmlFileExtensionValue
 }
 
+func (c *ConfigSettingsYaml) Jobs() int {
+	return c.JobsValue
+}
+
+func (c *ConfigSettingsYaml) PageTimeSource() PageTimeSource {
+	return c.PageTimeSourceValue
+}
+
+func (c *ConfigSettingsYaml) GitInfo() bool {
+	return c.GitInfoValue
+}
+
+func (c *ConfigSettingsYaml) BuildDrafts() bool {
+	return c.BuildDraftsValue
+}
+
+func (c *ConfigSettingsYaml) BuildFuture() bool {
+	return c.BuildFutureValue
+}
+
+func (c *ConfigSettingsYaml) BuildDir() string {
+	return c.BuildDirValue
+}
+
+func (c *ConfigSettingsYaml) BuildReport() string {
+	return c.BuildReportValue
+}
+
+func (c *ConfigSettingsYaml) Strict() bool {
+	return c.StrictValue
+}
+
+func (c *ConfigSettingsYaml) RedirectsNetlify() bool {
+	return c.RedirectsNetlifyValue
+}
+
+func (c *ConfigSettingsYaml) RedirectsNginx() bool {
+	return c.RedirectsNginxValue
+}
+
+func (c *ConfigSettingsYaml) PrettyUrls() bool {
+	return c.PrettyUrlsValue
+}
+
+func (c *ConfigSettingsYaml) TrailingSlash() TrailingSlash {
+	return c.TrailingSlashValue
+}
+
+func (c *ConfigSettingsYaml) Passthrough() []string {
+	return c.PassthroughValue
+}
+
+func (c *ConfigSettingsYaml) TocMinLevel() int {
+	return c.TocMinLevelValue
+}
+
+func (c *ConfigSettingsYaml) TocMaxLevel() int {
+	return c.TocMaxLevelValue
+}
+
 type ConfigYaml struct {
 	EnvValue      string             `env:"ENV"              env-default:"dev" yaml:"env"`
 	HttpValue     http.ConfigYaml    `env-prefix:"HTTP_"     yaml:"http"`
@@ -409,6 +504,7 @@ type SiteConfigYaml struct {
 	NameValue        string                     `env:"NAME"             env-default:"My Cool Website"             yaml:"name"`
 	DescriptionValue string                     `env:"DESCRIPTION"      env-default:"My Cool Website Description" yaml:"description"`
 	LangValue        string                     `env:"LANG"             env-default:"en"                          yaml:"lang"`
+	LanguagesValue   []*SiteLanguageConfigYaml  `yaml:"languages"`
 	AuthorValue      SiteConfigAuthorYaml       `env-prefix:"AUTHOR"    yaml:"author"`
 	LogoValue        SiteConfigLogoYaml         `env-prefix:"LOGO"      yaml:"logo"`
 	CopyrightValue   SiteConfigCopyrightYaml    `env-prefix:"COPYRIGHT" yaml:"copyright"`
@@ -434,6 +530,10 @@ func (c *SiteConfigYaml) Lang() string {
 	return c.LangValue
 }
 
+func (c *SiteConfigYaml) Languages() []SiteLanguageConfig {
+	return util.SliceOfRefsToInterfaces[SiteLanguageConfigYaml, SiteLanguageConfig](c.LanguagesValue)
+}
+
 func (c *SiteConfigYaml) Author() SiteConfigAuthor {
 	return &c.AuthorValue
 }
@@ -463,16 +563,18 @@ func (c *SiteConfigYaml) Template() SiteConfigTemplate {
 }
 
 type DirConfigYaml struct {
-	ThemeValue     string                                      `yaml:"theme"`
-	LayoutValue    string                                      `yaml:"layout"`
-	TitleValue     string                                      `yaml:"title"`
-	IsHiddenValue  bool                                        `yaml:"is_hidden"`
-	IsDraftValue   bool                                        `yaml:"is_draft"`
-	IsSystemValue  bool                                        `yaml:"is_system"`
-	VariablesValue map[string]any                              `yaml:"variables"`
-	ImportsValue   map[string][]*SiteConfigTemplateImportYaml  `yaml:"imports"`
-	IncludesValue  map[string][]*SiteConfigTemplateIncludeYaml `yaml:"includes"`
-	ExtrasValue    map[string][]*SiteConfigTemplateExtraYaml   `yaml:"extras"`
+	ThemeValue       string                                      `yaml:"theme"`
+	LayoutValue      string                                      `yaml:"layout"`
+	TitleValue       string                                      `yaml:"title"`
+	IsHiddenValue    bool                                        `yaml:"is_hidden"`
+	IsDraftValue     bool                                        `yaml:"is_draft"`
+	IsSystemValue    bool                                        `yaml:"is_system"`
+	PermalinkValue   string                                      `yaml:"permalink"`
+	PassthroughValue []string                                    `yaml:"passthrough"`
+	VariablesValue   map[string]any                              `yaml:"variables"`
+	ImportsValue     map[string][]*SiteConfigTemplateImportYaml  `yaml:"imports"`
+	IncludesValue    map[string][]*SiteConfigTemplateIncludeYaml `yaml:"includes"`
+	ExtrasValue      map[string][]*SiteConfigTemplateExtraYaml   `yaml:"extras"`
 }
 
 func (c *DirConfigYaml) Theme() string {
@@ -495,6 +597,14 @@ func (c *DirConfigYaml) IsDraft() bool {
 	return c.IsDraftValue
 }
 
+func (c *DirConfigYaml) Permalink() string {
+	return c.PermalinkValue
+}
+
+func (c *DirConfigYaml) Passthrough() []string {
+	return c.PassthroughValue
+}
+
 func (c *DirConfigYaml) Variables() map[string]any {
 	return c.VariablesValue
 }
@@ -520,6 +630,7 @@ func (c *DirConfigYaml) ToPageConfig(dir string) PageConfig {
 		c.IsHiddenValue,
 		c.IsDraftValue,
 		c.IsSystemValue,
+		c.PermalinkValue,
 		c.VariablesValue,
 		util.MapOfSlicesOfRefsToInterfaces[string, SiteConfigTemplateImportYaml, SiteConfigTemplateImport](c.ImportsValue),
 		util.MapOfSlicesOfRefsToInterfaces[string, SiteConfigTemplateIncludeYaml, SiteConfigTemplateInclude](c.IncludesValue),
@@ -530,15 +641,29 @@ func (c *DirConfigYaml) ToPageConfig(dir string) PageConfig {
 }
 
 type PageConfigYaml struct {
-	ThemeValue    string                                      `yaml:"theme"`
-	LayoutValue   string                                      `yaml:"layout"`
-	TitleValue    string                                      `yaml:"title"`
-	IsHiddenValue bool                                        `yaml:"is_hidden"`
-	IsDraftValue  bool                                        `yaml:"is_draft"`
-	IsSystemValue bool                                        `yaml:"is_system"`
-	ImportsValue  map[string][]*SiteConfigTemplateImportYaml  `yaml:"imports"`
-	IncludesValue map[string][]*SiteConfigTemplateIncludeYaml `yaml:"includes"`
-	ExtrasValue   map[string][]*SiteConfigTemplateExtraYaml   `yaml:"extras"`
+	ThemeValue     string                                      `yaml:"theme"`
+	LayoutValue    string                                      `yaml:"layout"`
+	TitleValue     string                                      `yaml:"title"`
+	IsHiddenValue  bool                                        `yaml:"is_hidden"`
+	IsDraftValue   bool                                        `yaml:"is_draft"`
+	IsSystemValue  bool                                        `yaml:"is_system"`
+	PermalinkValue string                                      `yaml:"permalink"`
+	ImportsValue   map[string][]*SiteConfigTemplateImportYaml  `yaml:"imports"`
+	IncludesValue  map[string][]*SiteConfigTemplateIncludeYaml `yaml:"includes"`
+	ExtrasValue    map[string][]*SiteConfigTemplateExtraYaml   `yaml:"extras"`
+}
+
+type PaginateConfigYaml struct {
+	CollectionValue string `yaml:"collection"`
+	SizeValue       int    `yaml:"size"`
+}
+
+func (c *PaginateConfigYaml) Collection() string {
+	return c.CollectionValue
+}
+
+func (c *PaginateConfigYaml) Size() int {
+	return c.SizeValue
 }
 
 func (c *PageConfigYaml) ToPageConfig(variables map[string]any) PageConfig {
@@ -550,6 +675,7 @@ func (c *PageConfigYaml) ToPageConfig(variables map[string]any) PageConfig {
 		c.IsHiddenValue,
 		c.IsDraftValue,
 		c.IsSystemValue,
+		c.PermalinkValue,
 		variables,
 		util.MapOfSlicesOfRefsToInterfaces[string, SiteConfigTemplateImportYaml, SiteConfigTemplateImport](c.ImportsValue),
 		util.MapOfSlicesOfRefsToInterfaces[string, SiteConfigTemplateIncludeYaml, SiteConfigTemplateInclude](c.IncludesValue),
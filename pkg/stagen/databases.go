@@ -41,7 +41,11 @@ func (s *Impl) loadDatabases(ctx context.Context) error {
 		}
 
 		if err = s.loadDatabase(ctx, databaseFilename); err != nil {
-			return fmt.Errorf("%w: %s: %w", ErrLoadDatabase, databaseFilename, err)
+			err = fmt.Errorf("%w: %s: %w", ErrLoadDatabase, databaseFilename, err)
+
+			if err = s.collectProblem(databaseFilename, err); err != nil {
+				return err
+			}
 		}
 	}
 
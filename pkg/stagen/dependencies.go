@@ -0,0 +1,84 @@
+package stagen
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+const dataDependencyPrefix = "data:"
+
+type DependencyGraph struct {
+	dependencies map[string]map[string]struct{}
+	dependants   map[string]map[string]struct{}
+	mutex        sync.RWMutex
+}
+
+func NewDependencyGraph() *DependencyGraph {
+	return &DependencyGraph{
+		dependencies: make(map[string]map[string]struct{}),
+		dependants:   make(map[string]map[string]struct{}),
+		mutex:        sync.RWMutex{},
+	}
+}
+
+func (g *DependencyGraph) Set(pageId string, dependencies []string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.remove(pageId)
+
+	pageDependencies := make(map[string]struct{}, len(dependencies))
+
+	for _, dependency := range dependencies {
+		dependency = filepath.Clean(dependency)
+
+		pageDependencies[dependency] = struct{}{}
+
+		if _, ok := g.dependants[dependency]; !ok {
+			g.dependants[dependency] = make(map[string]struct{})
+		}
+
+		g.dependants[dependency][pageId] = struct{}{}
+	}
+
+	g.dependencies[pageId] = pageDependencies
+}
+
+func (g *DependencyGraph) Remove(pageId string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.remove(pageId)
+}
+
+func (g *DependencyGraph) Dependants(dependency string) []string {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	return slices.Sorted(maps.Keys(g.dependants[filepath.Clean(dependency)]))
+}
+
+func (g *DependencyGraph) Dependencies(pageId string) []string {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	return slices.Sorted(maps.Keys(g.dependencies[pageId]))
+}
+
+func (g *DependencyGraph) remove(pageId string) {
+	for dependency := range g.dependencies[pageId] {
+		delete(g.dependants[dependency], pageId)
+
+		if len(g.dependants[dependency]) == 0 {
+			delete(g.dependants, dependency)
+		}
+	}
+
+	delete(g.dependencies, pageId)
+}
+
+func dataDependency(dataKey string) string {
+	return dataDependencyPrefix + dataKey
+}
@@ -23,7 +23,11 @@ func (s *Impl) loadExtensions(ctx context.Context) error {
 
 	for index, extension := range s.siteConfig.Extensions() {
 		if err := s.loadExtension(ctx, index, extension); err != nil {
-			return fmt.Errorf("%w: %s: %w", ErrLoadExtension, extension.Name(), err)
+			err = fmt.Errorf("%w: %s: %w", ErrLoadExtension, extension.Name(), err)
+
+			if err = s.collectProblem(filepath.Join(s.extensionsDir(), extension.Name()), err); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -62,11 +62,13 @@ type GeneratorSource interface {
 
 type Generator interface {
 	Config() SiteGeneratorConfig
+	ConfigFile() string
 	Generate(ctx context.Context) ([]Page, error)
 }
 
 type GeneratorImpl struct {
 	config             SiteGeneratorConfig
+	configFile         string
 	source             GeneratorSource
 	clock              clock.Clock
 	storage            storage.Storage
@@ -76,6 +78,7 @@ type GeneratorImpl struct {
 
 func NewGenerator(
 	config SiteGeneratorConfig,
+	configFile string,
 	source GeneratorSource,
 	clocks clock.Clock,
 	storage storage.Storage,
@@ -84,6 +87,7 @@ func NewGenerator(
 ) *GeneratorImpl {
 	return &GeneratorImpl{
 		config:             config,
+		configFile:         configFile,
 		source:             source,
 		clock:              clocks,
 		storage:            storage,
@@ -96,6 +100,11 @@ func (g *GeneratorImpl) Config() SiteGeneratorConfig {
 	return g.config
 }
 
+// ConfigFile is the file the generator is declared in
+func (g *GeneratorImpl) ConfigFile() string {
+	return g.configFile
+}
+
 func (g *GeneratorImpl) Generate(ctx context.Context) ([]Page, error) {
 	if g.source == nil {
 		return nil, ErrNoSource
@@ -177,6 +186,7 @@ func (g *GeneratorImpl) Generate(ctx context.Context) ([]Page, error) {
 		false,
 		false,
 		false,
+		"",
 		g.source.Variables(),
 		nil,
 		nil,
@@ -210,6 +220,7 @@ func (g *GeneratorImpl) Generate(ctx context.Context) ([]Page, error) {
 			"",
 			timeSpec,
 		)
+		pageFileInfo.SourceFilename = templatePath
 		pageFileInfo.IsTemplate = templatePageFileInfo.IsTemplate
 		pageFileInfo.IsMarkdown = templatePageFileInfo.IsMarkdown
 		pageFileInfo.IsHtml = templatePageFileInfo.IsHtml
@@ -2,6 +2,8 @@ package stagen
 
 import (
 	"context"
+	"maps"
+	"slices"
 )
 
 type AggDictGeneratorSource struct {
@@ -22,8 +24,12 @@ func NewAggDictGeneratorSource(
 func (s *AggDictGeneratorSource) Entries(_ context.Context) ([]GeneratorSourceEntry, error) {
 	entries := make([]GeneratorSourceEntry, 0)
 
-	for aggDictKey, aggDictValues := range s.aggDictData {
-		for aggDictValue, pages := range aggDictValues {
+	for _, aggDictKey := range slices.Sorted(maps.Keys(s.aggDictData)) {
+		aggDictValues := s.aggDictData[aggDictKey]
+
+		for _, aggDictValue := range slices.Sorted(maps.Keys(aggDictValues)) {
+			pages := aggDictValues[aggDictValue]
+
 			pagesIds := make([]string, len(pages))
 			for index, page := range pages {
 				pagesIds[index] = page.Id()
@@ -18,27 +18,41 @@ func (s *Impl) loadGenerators(ctx context.Context) error {
 	s.log.GetLogger(ctx).Info("Loading generators...")
 
 	generators := make([]SiteGeneratorConfig, 0)
+	generatorsPaths := make([]string, 0)
 
-	generators = append(generators, s.siteConfig.Generators()...)
+	for _, generator := range s.siteConfig.Generators() {
+		generators = append(generators, generator)
+		generatorsPaths = append(generatorsPaths, s.siteConfigFilename())
+	}
 
-	for _, extension := range s.extensions {
-		generators = append(generators, extension.Config().Generators()...)
+	for _, extension := range s.sortedExtensions() {
+		for _, generator := range extension.Config().Generators() {
+			generators = append(generators, generator)
+			generatorsPaths = append(generatorsPaths, extension.Path())
+		}
 	}
 
-	for _, theme := range s.themes {
-		generators = append(generators, theme.Config().Generators()...)
+	for _, theme := range s.sortedThemes() {
+		for _, generator := range theme.Config().Generators() {
+			generators = append(generators, generator)
+			generatorsPaths = append(generatorsPaths, theme.Path())
+		}
 	}
 
-	for _, generator := range generators {
-		if err := s.loadGenerator(ctx, generator); err != nil {
-			return fmt.Errorf("%w: %s: %w", ErrLoadGenerator, generator.Name(), err)
+	for index, generator := range generators {
+		if err := s.loadGenerator(ctx, generator, generatorsPaths[index]); err != nil {
+			err = fmt.Errorf("%w: %s: %w", ErrLoadGenerator, generator.Name(), err)
+
+			if err = s.collectProblem(generatorsPaths[index], err); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
-func (s *Impl) loadGenerator(ctx context.Context, generatorConfig SiteGeneratorConfig) error {
+func (s *Impl) loadGenerator(ctx context.Context, generatorConfig SiteGeneratorConfig, configFile string) error {
 	generatorName := generatorConfig.Name()
 	if generatorName == "" {
 		return ErrNoName
@@ -97,16 +111,17 @@ func (s *Impl) loadGenerator(ctx context.Context, generatorConfig SiteGeneratorC
 
 	templateDirs = append(templateDirs, filepath.Join(s.templatesDir(), "templates"))
 
-	for _, theme := range s.themes {
+	for _, theme := range s.sortedThemes() {
 		templateDirs = append(templateDirs, filepath.Join(theme.Path(), "templates"))
 	}
 
-	for _, extension := range s.extensions {
+	for _, extension := range s.sortedExtensions() {
 		templateDirs = append(templateDirs, filepath.Join(extension.Path(), "templates"))
 	}
 
 	s.generators[generatorName] = NewGenerator(
 		generatorConfig,
+		configFile,
 		generatorSource,
 		s.clock,
 		s.storage,
@@ -120,9 +135,21 @@ func (s *Impl) loadGenerator(ctx context.Context, generatorConfig SiteGeneratorC
 func (s *Impl) buildGenerators(ctx context.Context) error {
 	s.log.GetLogger(ctx).Info("Building generators...")
 
-	for _, generator := range s.generators {
+	// the pages of the previous pass are generated again,
+	// so any page met twice within a pass is overwritten for real
+	for pageId := range s.generatedPages {
+		delete(s.pages, pageId)
+	}
+
+	s.generatedPages = make(map[string]string)
+
+	for _, generator := range s.sortedGenerators() {
 		if err := s.buildGenerator(ctx, generator); err != nil {
-			return fmt.Errorf("%w: %s: %w", ErrGeneratorBuildFailed, generator.Config().Name(), err)
+			err = fmt.Errorf("%w: %s: %w", ErrGeneratorBuildFailed, generator.Config().Name(), err)
+
+			if err = s.collectProblem(generator.ConfigFile(), err); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -139,8 +166,57 @@ func (s *Impl) buildGenerator(ctx context.Context, generator Generator) error {
 		return fmt.Errorf("%w: %s: %w", ErrGeneratorBuildFailed, generatorName, err)
 	}
 
+	reportGenerator := BuildReportGenerator{
+		Name:    generatorName,
+		Pages:   0,
+		Skipped: 0,
+	}
+
+	defer func() {
+		s.report.AddGenerator(reportGenerator)
+	}()
+
 	for _, page := range pages {
+		skipReason, err := s.pageSkipReason(page)
+		if err != nil {
+			return fmt.Errorf("%w: %s: page '%s': %w", ErrGeneratorBuildFailed, generatorName, page.Id(), err)
+		}
+
+		if skipReason != "" {
+			s.log.GetLogger(ctx).Infof("Skipping %s page '%s'", skipReason, page.Id())
+
+			reportGenerator.Skipped++
+
+			continue
+		}
+
+		if existingGeneratorName, ok := s.generatedPages[page.Id()]; ok {
+			if existingGeneratorName == generatorName {
+				s.warnf(ctx, generator.ConfigFile(), "Page '%s' is generated more than once by generator '%s'", page.Id(), generatorName)
+			} else {
+				s.warnf(
+					ctx,
+					generator.ConfigFile(),
+					"Page '%s' of generator '%s' is overwritten by generator '%s'",
+					page.Id(),
+					existingGeneratorName,
+					generatorName,
+				)
+			}
+		} else if existingPage, ok := s.pages[page.Id()]; ok {
+			s.warnf(
+				ctx,
+				existingPage.FileInfo().Filename,
+				"Page '%s' is overwritten by generator '%s'",
+				page.Id(),
+				generatorName,
+			)
+		}
+
 		s.pages[page.Id()] = page
+		s.generatedPages[page.Id()] = generatorName
+
+		reportGenerator.Pages++
 	}
 
 	return nil
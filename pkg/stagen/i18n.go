@@ -0,0 +1,441 @@
+package stagen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"maps"
+	"net/url"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	i18nDir          = "i18n"
+	hreflangXDefault = "x-default"
+)
+
+var (
+	ErrInvalidLanguage = errors.New("invalid language")
+	ErrLoadI18n        = errors.New("load i18n")
+)
+
+type Language struct {
+	Code      string
+	Name      string
+	Prefix    string
+	IsDefault bool
+}
+
+type PageTranslation struct {
+	Lang      string
+	LangName  string
+	Id        string
+	Uri       string
+	Url       string
+	IsDefault bool
+}
+
+// loadLanguages reads the site languages, a site without them has the single site lang,
+// the default language is the site lang or the first one and has no prefix unless one is configured
+func (s *Impl) loadLanguages(ctx context.Context) error {
+	s.log.GetLogger(ctx).Info("Loading languages...")
+
+	// broken languages are checked with the single site lang
+	s.languages = []*Language{
+		{
+			Code:      s.siteConfig.Lang(),
+			Name:      s.siteConfig.Lang(),
+			Prefix:    "",
+			IsDefault: true,
+		},
+	}
+
+	languages, err := s.configLanguages()
+	if err != nil {
+		return s.collectProblem(s.siteConfigFilename(), err)
+	}
+
+	s.languages = languages
+
+	return nil
+}
+
+func (s *Impl) configLanguages() ([]*Language, error) {
+	languagesConfigs := s.siteConfig.Languages()
+
+	languages := make([]*Language, 0, max(1, len(languagesConfigs)))
+
+	for _, languageConfig := range languagesConfigs {
+		code := languageConfig.Code()
+		if code == "" || strings.ContainsAny(code, "./ ") {
+			return nil, fmt.Errorf("%w: code '%s'", ErrInvalidLanguage, code)
+		}
+
+		name := languageConfig.Name()
+		if name == "" {
+			name = code
+		}
+
+		languages = append(languages, &Language{
+			Code:      code,
+			Name:      name,
+			Prefix:    strings.Trim(languageConfig.Prefix(), "/"),
+			IsDefault: false,
+		})
+	}
+
+	defaultIndex := slices.IndexFunc(languages, func(language *Language) bool {
+		return language.Code == s.siteConfig.Lang()
+	})
+
+	switch {
+	case len(languages) == 0:
+		languages = append(languages, &Language{
+			Code:      s.siteConfig.Lang(),
+			Name:      s.siteConfig.Lang(),
+			Prefix:    "",
+			IsDefault: false,
+		})
+
+		defaultIndex = 0
+
+	case defaultIndex < 0:
+		defaultIndex = 0
+	}
+
+	codes := make(map[string]struct{}, len(languages))
+	prefixes := make(map[string]struct{}, len(languages))
+
+	for index, language := range languages {
+		language.IsDefault = index == defaultIndex
+
+		if !language.IsDefault && language.Prefix == "" {
+			language.Prefix = language.Code
+		}
+
+		if _, ok := codes[language.Code]; ok {
+			return nil, fmt.Errorf("%w: duplicate code '%s'", ErrInvalidLanguage, language.Code)
+		}
+
+		codes[language.Code] = struct{}{}
+
+		if _, ok := prefixes[language.Prefix]; ok {
+			return nil, fmt.Errorf("%w: duplicate prefix '%s'", ErrInvalidLanguage, language.Prefix)
+		}
+
+		prefixes[language.Prefix] = struct{}{}
+	}
+
+	return languages, nil
+}
+
+func (s *Impl) defaultLanguage() *Language {
+	for _, language := range s.languages {
+		if language.IsDefault {
+			return language
+		}
+	}
+
+	return &Language{
+		Code:      s.siteConfig.Lang(),
+		Name:      s.siteConfig.Lang(),
+		Prefix:    "",
+		IsDefault: true,
+	}
+}
+
+func (s *Impl) languageByCode(code string) (*Language, bool) {
+	for _, language := range s.languages {
+		if language.Code == code {
+			return language, true
+		}
+	}
+
+	return nil, false
+}
+
+func (s *Impl) languageByPrefix(prefix string) (*Language, bool) {
+	for _, language := range s.languages {
+		if language.Prefix != "" && language.Prefix == prefix {
+			return language, true
+		}
+	}
+
+	return nil, false
+}
+
+// splitPageLang cuts the language code off the page filename,
+// it goes right before the page extension: about.de.md, about.de.html.tmpl
+func (s *Impl) splitPageLang(pageFilename string) (string, string) {
+	dir, base := filepath.Split(pageFilename)
+
+	parts := strings.Split(base, ".")
+
+	langIndex := len(parts) - 2
+	if len(parts) > 2 && templateExtensionRegexp.MatchString("."+parts[len(parts)-1]) {
+		langIndex--
+	}
+
+	if langIndex < 1 {
+		return pageFilename, ""
+	}
+
+	language, ok := s.languageByCode(parts[langIndex])
+	if !ok {
+		return pageFilename, ""
+	}
+
+	return dir + strings.Join(slices.Delete(parts, langIndex, langIndex+1), "."), language.Code
+}
+
+// pageLanguage is the language of the file suffix or of the language dir,
+// it returns the page name under the language prefix and the name shared by the translations
+func (s *Impl) pageLanguage(pageFileInfo *PageFileInfo, pageName string) (*Language, string, string) {
+	pageName = filepath.ToSlash(pageName)
+
+	if language, ok := s.languageByCode(pageFileInfo.Lang); ok {
+		return language, path.Join(language.Prefix, pageName), pageName
+	}
+
+	prefix, translationKey, ok := strings.Cut(pageName, "/")
+
+	if language, found := s.languageByPrefix(prefix); found && ok {
+		return language, pageName, translationKey
+	}
+
+	language := s.defaultLanguage()
+
+	return language, path.Join(language.Prefix, pageName), pageName
+}
+
+// loadTranslations links the pages with the same translation key in different languages
+func (s *Impl) loadTranslations(ctx context.Context) error {
+	s.log.GetLogger(ctx).Info("Loading translations...")
+
+	s.pagesTranslations = make(map[string][]*PageTranslation)
+
+	groups := make(map[string][]Page)
+
+	for _, page := range s.sortedPages() {
+		if page.Config().IsSystem() {
+			continue
+		}
+
+		translationKey := page.FileInfo().TranslationKey
+
+		groups[translationKey] = append(groups[translationKey], page)
+	}
+
+	for _, pages := range groups {
+		if len(pages) < 2 {
+			continue
+		}
+
+		translations := make([]*PageTranslation, 0, len(pages))
+
+		for _, language := range s.languages {
+			for _, page := range pages {
+				if page.FileInfo().Lang != language.Code {
+					continue
+				}
+
+				pageUrl, err := url.JoinPath(s.siteConfig.BaseUrl(), page.Uri())
+				if err != nil {
+					err = fmt.Errorf("failed to resolve page '%s' url: %w", page.Id(), err)
+
+					if err = s.collectProblem(page.FileInfo().Filename, err); err != nil {
+						return err
+					}
+
+					continue
+				}
+
+				translations = append(translations, &PageTranslation{
+					Lang:      language.Code,
+					LangName:  language.Name,
+					Id:        page.Id(),
+					Uri:       page.Uri(),
+					Url:       pageUrl,
+					IsDefault: language.IsDefault,
+				})
+			}
+		}
+
+		for _, translation := range translations {
+			s.pagesTranslations[translation.Id] = translations
+		}
+	}
+
+	return nil
+}
+
+// pageTranslations are the other languages of the page
+func (s *Impl) pageTranslations(pageId string) []*PageTranslation {
+	translations := make([]*PageTranslation, 0)
+
+	for _, translation := range s.pagesTranslations[pageId] {
+		if translation.Id != pageId {
+			translations = append(translations, translation)
+		}
+	}
+
+	return translations
+}
+
+// pageAlternates are all the languages of the page including itself
+func (s *Impl) pageAlternates(pageId string) []*PageTranslation {
+	alternates, ok := s.pagesTranslations[pageId]
+	if !ok {
+		return make([]*PageTranslation, 0)
+	}
+
+	return alternates
+}
+
+func translationsEqual(a []*PageTranslation, b []*PageTranslation) bool {
+	return slices.EqualFunc(a, b, func(aTranslation *PageTranslation, bTranslation *PageTranslation) bool {
+		return *aTranslation == *bTranslation
+	})
+}
+
+// loadI18n reads the i18n/<lang>.yaml dictionaries of the themes and of the site,
+// the site strings override the themes ones and missing strings fall back to the default language
+func (s *Impl) loadI18n(ctx context.Context) error {
+	s.log.GetLogger(ctx).Info("Loading i18n...")
+
+	dirs := make([]string, 0, len(s.themes)+1)
+
+	for _, themeId := range slices.Sorted(maps.Keys(s.themes)) {
+		dirs = append(dirs, filepath.Join(s.themes[themeId].Path(), i18nDir))
+	}
+
+	dirs = append(dirs, filepath.Join(s.workDir, i18nDir))
+
+	dictionaries := make(map[string]map[string]string, len(s.languages))
+
+	for _, language := range s.languages {
+		dictionary := make(map[string]string)
+
+		for _, dir := range dirs {
+			for _, ext := range []string{".yaml", ".yml"} {
+				filename := filepath.Join(dir, language.Code+ext)
+
+				if err := s.readI18nFile(ctx, filename, dictionary); err != nil {
+					if err = s.collectProblem(filename, fmt.Errorf("%w: %w", ErrLoadI18n, err)); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		dictionaries[language.Code] = dictionary
+	}
+
+	defaultDictionary := dictionaries[s.defaultLanguage().Code]
+
+	s.i18n = make(map[string]map[string]string, len(dictionaries))
+
+	for code, dictionary := range dictionaries {
+		s.i18n[code] = make(map[string]string, len(defaultDictionary)+len(dictionary))
+
+		maps.Copy(s.i18n[code], defaultDictionary)
+		maps.Copy(s.i18n[code], dictionary)
+	}
+
+	return nil
+}
+
+func (s *Impl) readI18nFile(ctx context.Context, filename string, dictionary map[string]string) error {
+	if exists, err := s.storage.FileExists(ctx, filename); err != nil {
+		return fmt.Errorf("failed to check if file %s exists: %w", filename, err)
+	} else if !exists {
+		return nil
+	}
+
+	content, err := s.readFile(ctx, filename)
+	if err != nil {
+		return err
+	}
+
+	var values map[string]any
+
+	if err = yaml.Unmarshal(content, &values); err != nil {
+		return fmt.Errorf("failed to parse file %s: %w", filename, err)
+	}
+
+	flattenI18n("", values, dictionary)
+
+	return nil
+}
+
+// flattenI18n joins the keys of nested maps with dots
+func flattenI18n(prefix string, values map[string]any, dictionary map[string]string) {
+	for key, value := range values {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch value := value.(type) {
+		case map[string]any:
+			flattenI18n(key, value, dictionary)
+
+		case nil:
+			dictionary[key] = ""
+
+		default:
+			dictionary[key] = fmt.Sprint(value)
+		}
+	}
+}
+
+func (s *Impl) pageI18n(page Page) map[string]string {
+	dictionary, ok := s.i18n[page.FileInfo().Lang]
+	if !ok {
+		return make(map[string]string)
+	}
+
+	return dictionary
+}
+
+// translate looks the key up in the template data dictionary,
+// unknown keys are returned as is and args are formatted into the string
+func translate(data map[string]any, key string, args ...any) string {
+	dictionary, _ := data["I18n"].(map[string]string)
+
+	value, ok := dictionary[key]
+	if !ok {
+		value = key
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(value, args...)
+	}
+
+	return value
+}
+
+// renderHreflang renders the alternate links of the page languages,
+// the default language is the x-default one
+func renderHreflang(alternates []*PageTranslation) string {
+	result := strings.Builder{}
+
+	for _, alternate := range alternates {
+		result.WriteString(hreflangLink(alternate.Lang, alternate.Url))
+
+		if alternate.IsDefault {
+			result.WriteString(hreflangLink(hreflangXDefault, alternate.Url))
+		}
+	}
+
+	return result.String()
+}
+
+func hreflangLink(lang string, href string) string {
+	return `<link rel="alternate" hreflang="` + html.EscapeString(lang) + `" href="` + html.EscapeString(href) + `">` + "\n"
+}
@@ -50,24 +50,28 @@ func (s *Impl) init(ctx context.Context) error {
 
 	log.Info("Initializing stagen...")
 
-	log.Infof("Creating build dir...")
-
-	buildDir := s.buildDir()
+	s.buildTime = s.clock.Now()
 
-	if err := s.storage.MkDir(ctx, buildDir); err != nil {
-		return fmt.Errorf("failed to create build dir: %w", err)
+	if !s.fixedBuildTime.IsZero() {
+		s.buildTime = s.fixedBuildTime
 	}
 
-	s.buildTime = s.clock.Now()
-
 	if err := s.loadExtensions(ctx); err != nil {
 		return fmt.Errorf("%w: error loading extensions: %w", ErrInit, err)
 	}
 
+	if err := s.loadLanguages(ctx); err != nil {
+		return fmt.Errorf("%w: error loading languages: %w", ErrInit, err)
+	}
+
 	if err := s.loadDatabases(ctx); err != nil {
 		return fmt.Errorf("%w: error loading databases: %w", ErrInit, err)
 	}
 
+	if s.check == nil {
+		s.loadGitInfo(ctx)
+	}
+
 	if err := s.loadPages(ctx); err != nil {
 		return fmt.Errorf("%w: error loading pages: %w", ErrInit, err)
 	}
@@ -94,6 +98,29 @@ func (s *Impl) init(ctx context.Context) error {
 		}
 	}
 
+	if err := s.loadTranslations(ctx); err != nil {
+		return fmt.Errorf("%w: error loading translations: %w", ErrInit, err)
+	}
+
+	if err := s.loadI18n(ctx); err != nil {
+		return fmt.Errorf("%w: error loading i18n: %w", ErrInit, err)
+	}
+
+	s.loadSections(ctx)
+
+	if err := s.loadPaginators(ctx); err != nil {
+		return fmt.Errorf("%w: error loading paginators: %w", ErrInit, err)
+	}
+
+	// the aliases go after the pager pages, so they are checked against their output too
+	if err := s.loadAliases(ctx); err != nil {
+		return fmt.Errorf("%w: error loading aliases: %w", ErrInit, err)
+	}
+
+	if err := s.loadPagesTexts(ctx); err != nil {
+		return fmt.Errorf("%w: error loading pages texts: %w", ErrInit, err)
+	}
+
 	log.Info("Initialization complete")
 
 	s.initialized = true
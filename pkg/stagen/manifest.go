@@ -0,0 +1,218 @@
+package stagen
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/pixality-inc/golang-core/json"
+)
+
+const buildManifestFilename = "build-manifest.json"
+
+type BuildManifest struct {
+	BuildDir string            `json:"build_dir"`
+	Files    map[string]string `json:"files"`
+	mutex    sync.RWMutex
+}
+
+func NewBuildManifest(buildDir string) *BuildManifest {
+	return &BuildManifest{
+		BuildDir: buildDir,
+		Files:    make(map[string]string),
+		mutex:    sync.RWMutex{},
+	}
+}
+
+func (m *BuildManifest) Get(filename string) (string, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	hash, ok := m.Files[filename]
+
+	return hash, ok
+}
+
+func (m *BuildManifest) Set(filename string, hash string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.Files[filename] = hash
+}
+
+func (m *BuildManifest) Delete(filename string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.Files, filename)
+}
+
+func (m *BuildManifest) Filenames() []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return slices.Sorted(maps.Keys(m.Files))
+}
+
+func (m *BuildManifest) Clone() *BuildManifest {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return &BuildManifest{
+		BuildDir: m.BuildDir,
+		Files:    maps.Clone(m.Files),
+		mutex:    sync.RWMutex{},
+	}
+}
+
+func (s *Impl) stagenDir() string {
+	return filepath.Join(s.workDir, ".stagen")
+}
+
+func (s *Impl) buildManifestFilename() string {
+	return filepath.Join(s.stagenDir(), buildManifestFilename)
+}
+
+func (s *Impl) loadBuildManifest(ctx context.Context) (*BuildManifest, error) {
+	buildDir := s.buildDir()
+	manifestFilename := s.buildManifestFilename()
+
+	if exists, err := s.storage.FileExists(ctx, manifestFilename); err != nil {
+		return nil, fmt.Errorf("failed to check if file %s exists: %w", manifestFilename, err)
+	} else if !exists {
+		return NewBuildManifest(buildDir), nil
+	}
+
+	manifestContent, err := s.readFile(ctx, manifestFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := NewBuildManifest(buildDir)
+
+	if err = json.Unmarshal(manifestContent, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse build manifest %s: %w", manifestFilename, err)
+	}
+
+	// the manifest of another output directory tells nothing about this one
+	if manifest.BuildDir != buildDir || manifest.Files == nil {
+		return NewBuildManifest(buildDir), nil
+	}
+
+	return manifest, nil
+}
+
+func (s *Impl) saveBuildManifest(ctx context.Context) error {
+	if err := s.storage.MkDir(ctx, s.stagenDir()); err != nil {
+		return fmt.Errorf("failed to create directory '%s': %w", s.stagenDir(), err)
+	}
+
+	s.buildManifest.mutex.RLock()
+	manifestContent, err := json.Marshal(s.buildManifest)
+	s.buildManifest.mutex.RUnlock()
+
+	if err != nil {
+		return fmt.Errorf("failed to marshal build manifest: %w", err)
+	}
+
+	manifestFilename := s.buildManifestFilename()
+
+	if err = s.storage.Write(ctx, manifestFilename, bytes.NewReader(manifestContent)); err != nil {
+		return fmt.Errorf("failed to save build manifest %s: %w", manifestFilename, err)
+	}
+
+	return nil
+}
+
+func (s *Impl) reuseBuildFile(ctx context.Context, filename string, hash string) (bool, error) {
+	previousHash, ok := s.previousBuildManifest.Get(filename)
+	if !ok || previousHash != hash {
+		return false, nil
+	}
+
+	buildFilename := filepath.Join(s.buildDir(), filename)
+
+	exists, err := s.storage.FileExists(ctx, buildFilename)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if file %s exists: %w", buildFilename, err)
+	} else if !exists {
+		return false, nil
+	}
+
+	if s.stagingDir == "" {
+		return true, nil
+	}
+
+	if err = s.linkBuildFile(ctx, filename); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *Impl) removeBuildFile(ctx context.Context, filename string) error {
+	s.buildManifest.Delete(filename)
+	s.report.RemoveOutput(filename)
+
+	buildDir := s.outputDir()
+	buildFilename := filepath.Join(buildDir, filename)
+
+	if exists, err := s.storage.FileExists(ctx, buildFilename); err != nil {
+		return fmt.Errorf("failed to check if file %s exists: %w", buildFilename, err)
+	} else if exists {
+		if err = s.storage.DeleteFile(ctx, buildFilename); err != nil {
+			return fmt.Errorf("failed to delete file %s: %w", buildFilename, err)
+		}
+	}
+
+	for dir := filepath.Dir(buildFilename); dir != buildDir && dir != "." && dir != "/"; dir = filepath.Dir(dir) {
+		entries, err := s.storage.ReadDir(ctx, dir)
+		if err != nil || len(entries) > 0 {
+			break
+		}
+
+		if err = s.storage.DeleteDir(ctx, dir); err != nil {
+			return fmt.Errorf("failed to delete directory %s: %w", dir, err)
+		}
+
+		s.dirsMutex.Lock()
+		delete(s.createdDirs, dir)
+		s.dirsMutex.Unlock()
+	}
+
+	return nil
+}
+
+func contentHash(content []byte) string {
+	hash := sha256.Sum256(content)
+
+	return hex.EncodeToString(hash[:])
+}
+
+func (s *Impl) fileHash(ctx context.Context, filename string) (string, error) {
+	file, err := s.storage.ReadFile(ctx, filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+
+	defer func() {
+		if fErr := file.Close(); fErr != nil {
+			s.log.GetLogger(ctx).WithError(fErr).Errorf("failed to close storage file: %s", filename)
+		}
+	}()
+
+	hash := sha256.New()
+
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -69,7 +69,7 @@ func (s *Impl) NewProject(ctx context.Context, name string, withGit bool) error
 			StacktraceErrorsValue: false,
 		},
 		Stagen: ConfigYaml{
-			EnvValue: "dev",
+			EnvValue: EnvDev,
 			HttpValue: http.ConfigYaml{
 				HostValue:            "127.0.0.1",
 				PortValue:            8001,
@@ -77,6 +77,21 @@ func (s *Impl) NewProject(ctx context.Context, name string, withGit bool) error
 			},
 			SettingsValue: ConfigSettingsYaml{
 				UseUriHtmlFileExtensionValue: false,
+				JobsValue:                    0,
+				PageTimeSourceValue:          PageTimeSourceFs,
+				GitInfoValue:                 true,
+				BuildDraftsValue:             false,
+				BuildFutureValue:             false,
+				BuildDirValue:                defaultBuildDir,
+				BuildReportValue:             "",
+				StrictValue:                  false,
+				RedirectsNetlifyValue:        false,
+				RedirectsNginxValue:          false,
+				PrettyUrlsValue:              false,
+				TrailingSlashValue:           TrailingSlashAlways,
+				PassthroughValue:             []string{},
+				TocMinLevelValue:             2,
+				TocMaxLevelValue:             3,
 			},
 		},
 		Site: SiteConfigYaml{
@@ -84,6 +99,7 @@ func (s *Impl) NewProject(ctx context.Context, name string, withGit bool) error
 			NameValue:        name,
 			DescriptionValue: "",
 			LangValue:        "en",
+			LanguagesValue:   nil,
 			AuthorValue: SiteConfigAuthorYaml{
 				NameValue:    "",
 				EmailValue:   "",
@@ -162,7 +178,8 @@ data:
 	gitignore := []byte(`.DS_Store
 .idea
 
-/build`)
+/build
+/.stagen`)
 
 	gitKeep := []byte(``)
 
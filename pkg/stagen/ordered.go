@@ -0,0 +1,118 @@
+package stagen
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const pageWeightVariable = "weight"
+
+var ErrInvalidPageWeight = errors.New("invalid page weight")
+
+// maps are iterated in random order, so everything affecting the output goes through these
+
+func (s *Impl) sortedExtensions() []Extension {
+	return slices.SortedFunc(maps.Values(s.extensions), func(a, b Extension) int {
+		return a.Index() - b.Index()
+	})
+}
+
+func (s *Impl) sortedThemes() []Theme {
+	return slices.SortedFunc(maps.Values(s.themes), func(a, b Theme) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+}
+
+func (s *Impl) sortedGenerators() []Generator {
+	return slices.SortedFunc(maps.Values(s.generators), func(a, b Generator) int {
+		return strings.Compare(a.Config().Name(), b.Config().Name())
+	})
+}
+
+func (s *Impl) sortedAggDicts() []SiteAggDictConfig {
+	return slices.SortedFunc(maps.Values(s.aggDicts), func(a, b SiteAggDictConfig) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+}
+
+func (s *Impl) sortedPages() []Page {
+	return sortPages(slices.Collect(maps.Values(s.pages)))
+}
+
+func sortPages(pages []Page) []Page {
+	return slices.SortedFunc(slices.Values(pages), func(a, b Page) int {
+		return strings.Compare(a.Id(), b.Id())
+	})
+}
+
+// orderedPages are the pages by weight, then newest first, then by id
+func (s *Impl) orderedPages(ctx context.Context) []Page {
+	pages := s.sortedPages()
+
+	for _, page := range pages {
+		if _, _, err := pageWeight(page); err != nil {
+			s.warnf(ctx, page.FileInfo().Filename, "Failed to parse '%s': %v", pageWeightVariable, err)
+		}
+	}
+
+	return sortPagesByWeight(pages)
+}
+
+func sortPagesByWeight(pages []Page) []Page {
+	slices.SortStableFunc(pages, comparePages)
+
+	return pages
+}
+
+// comparePages puts pages with a weight first
+func comparePages(a Page, b Page) int {
+	weightA, hasWeightA, _ := pageWeight(a) //nolint:errcheck
+	weightB, hasWeightB, _ := pageWeight(b) //nolint:errcheck
+
+	switch {
+	case hasWeightA && hasWeightB && weightA != weightB:
+		return cmp.Compare(weightA, weightB)
+
+	case hasWeightA && !hasWeightB:
+		return -1
+
+	case !hasWeightA && hasWeightB:
+		return 1
+	}
+
+	if result := b.FileInfo().CreatedAt.Compare(a.FileInfo().CreatedAt); result != 0 {
+		return result
+	}
+
+	return strings.Compare(a.Id(), b.Id())
+}
+
+func pageWeight(page Page) (int, bool, error) {
+	switch value := page.Config().Variables()[pageWeightVariable].(type) {
+	case nil:
+		return 0, false, nil
+
+	case int:
+		return value, true, nil
+
+	case float64:
+		return int(value), true, nil
+
+	case string:
+		weight, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, false, fmt.Errorf("%w: %s", ErrInvalidPageWeight, value)
+		}
+
+		return weight, true, nil
+
+	default:
+		return 0, false, fmt.Errorf("%w: %v", ErrInvalidPageWeight, value)
+	}
+}
@@ -10,6 +10,10 @@ import (
 
 type PageFileInfo struct {
 	Filename                      string
+	SourceFilename                string
+	ContentLine                   int
+	OutputFilename                string
+	Resources                     []*PageResource
 	BaseFilename                  string
 	Path                          string
 	PathWithoutWorkDir            string
@@ -25,6 +29,8 @@ type PageFileInfo struct {
 	ModifiedAt                    time.Time
 	AccessedAt                    time.Time
 	ChangedAt                     time.Time
+	Lang                          string
+	TranslationKey                string
 }
 
 func NewPageFileInfo(
@@ -58,6 +64,10 @@ func NewPageFileInfo(
 
 	pageFileInfo := &PageFileInfo{
 		Filename:                      pageFilename,
+		SourceFilename:                pageFilename,
+		ContentLine:                   1,
+		OutputFilename:                "",
+		Resources:                     nil,
 		BaseFilename:                  filepath.Base(pageFilename),
 		Path:                          pageDir,
 		PathWithoutWorkDir:            pageDirWithoutWorkDir,
@@ -73,6 +83,8 @@ func NewPageFileInfo(
 		ModifiedAt:                    stat.ModTime(),
 		AccessedAt:                    stat.AccessTime(),
 		ChangedAt:                     stat.ChangeTime(),
+		Lang:                          "",
+		TranslationKey:                "",
 	}
 
 	return pageFileInfo
@@ -0,0 +1,104 @@
+package stagen
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+type PageGitAuthor struct {
+	Name  string
+	Email string
+}
+
+type PageGitInfo struct {
+	CreatedAt  time.Time
+	ModifiedAt time.Time
+	Authors    []PageGitAuthor
+	Hash       string
+	Subject    string
+}
+
+func (s *Impl) loadGitInfo(ctx context.Context) {
+	s.pagesGitInfo = make(map[string]*PageGitInfo)
+
+	if !s.config.Settings().GitInfo() {
+		return
+	}
+
+	log := s.log.GetLogger(ctx)
+
+	log.Info("Loading git info...")
+
+	// a project outside of a git checkout is a setup condition, not a problem of the content
+	if !s.git.HasGit(ctx) {
+		log.Info("Git is not installed, skipping git info")
+
+		return
+	}
+
+	if !s.git.IsRepository(ctx, s.realWorkDir) {
+		log.Info("Project is not a git repository, skipping git info")
+
+		return
+	}
+
+	commits, err := s.git.Log(ctx, s.realWorkDir, s.pagesDir())
+	if err != nil {
+		s.warnf(ctx, s.pagesDir(), "Failed to read git log, skipping git info: %v", err)
+
+		return
+	}
+
+	// commits are listed newest first
+	for _, commit := range slices.Backward(commits) {
+		author := PageGitAuthor{
+			Name:  commit.AuthorName,
+			Email: commit.AuthorEmail,
+		}
+
+		for _, filename := range commit.Files {
+			gitInfo, ok := s.pagesGitInfo[filename]
+			if !ok {
+				gitInfo = &PageGitInfo{
+					CreatedAt:  commit.AuthorDate,
+					ModifiedAt: time.Time{},
+					Authors:    make([]PageGitAuthor, 0),
+					Hash:       "",
+					Subject:    "",
+				}
+
+				s.pagesGitInfo[filename] = gitInfo
+			}
+
+			gitInfo.ModifiedAt = commit.AuthorDate
+			gitInfo.Hash = commit.Hash
+			gitInfo.Subject = commit.Subject
+
+			if !slices.Contains(gitInfo.Authors, author) {
+				gitInfo.Authors = append(gitInfo.Authors, author)
+			}
+		}
+	}
+}
+
+func (s *Impl) pageGitData(page Page) map[string]any {
+	gitInfo, ok := s.pagesGitInfo[page.FileInfo().Filename]
+	if !ok {
+		gitInfo = &PageGitInfo{
+			CreatedAt:  time.Time{},
+			ModifiedAt: time.Time{},
+			Authors:    make([]PageGitAuthor, 0),
+			Hash:       "",
+			Subject:    "",
+		}
+	}
+
+	return map[string]any{
+		"CreatedAt":  gitInfo.CreatedAt,
+		"ModifiedAt": gitInfo.ModifiedAt,
+		"Authors":    gitInfo.Authors,
+		"Hash":       gitInfo.Hash,
+		"Subject":    gitInfo.Subject,
+	}
+}
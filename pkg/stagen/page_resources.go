@@ -0,0 +1,141 @@
+package stagen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+
+	"github.com/stagens/stagen/pkg/filetree"
+)
+
+const (
+	bundleIndexName     = "index"
+	mimeTypeHeaderBytes = 3072
+)
+
+type PageResource struct {
+	Name     string
+	Uri      string
+	MimeType string
+	Size     int64
+	Source   string
+	Output   string
+}
+
+// bundleIndexFilename returns the index page of a page bundle directory, if it is one
+func bundleIndexFilename(entries []filetree.Entry) string {
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		filename, extensions := removeFileExtension(entry.Name())
+		if filename != bundleIndexName {
+			continue
+		}
+
+		if markdownExtensionRegexp.MatchString(extensions) || htmlExtensionRegexp.MatchString(extensions) {
+			return filepath.Join(entry.Path(), entry.Name())
+		}
+	}
+
+	return ""
+}
+
+func isPageFilename(filename string) bool {
+	_, extensions := removeFileExtension(filename)
+
+	return markdownExtensionRegexp.MatchString(extensions) ||
+		htmlExtensionRegexp.MatchString(extensions) ||
+		templateExtensionRegexp.MatchString(extensions)
+}
+
+func (s *Impl) loadPageResources(ctx context.Context, pageFilename string, filenames []string) ([]*PageResource, error) {
+	resources := make([]*PageResource, 0, len(filenames))
+
+	bundleDir := filepath.Dir(pageFilename)
+
+	for _, filename := range filenames {
+		name, err := filepath.Rel(bundleDir, filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve resource '%s' name: %w", filename, err)
+		}
+
+		mimeType, size, err := s.resourceInfo(ctx, filename)
+		if err != nil {
+			return nil, err
+		}
+
+		resources = append(resources, &PageResource{
+			Name:     filepath.ToSlash(name),
+			Uri:      "",
+			MimeType: mimeType,
+			Size:     size,
+			Source:   filename,
+			Output:   "",
+		})
+	}
+
+	return resources, nil
+}
+
+// resourceInfo reads only the header of the resource, the size comes from its stat
+func (s *Impl) resourceInfo(ctx context.Context, filename string) (string, int64, error) {
+	localFilename, err := s.localPath(ctx, filename)
+	if err != nil {
+		return "", 0, err
+	}
+
+	stat, err := os.Stat(localFilename)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to stat file %s: %w", filename, err)
+	}
+
+	mimeType := mime.TypeByExtension(filepath.Ext(filename))
+	if mimeType != "" {
+		return mimeType, stat.Size(), nil
+	}
+
+	file, err := s.storage.ReadFile(ctx, filename)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+
+	defer func() {
+		if fErr := file.Close(); fErr != nil {
+			s.log.GetLogger(ctx).WithError(fErr).Errorf("failed to close storage file: %s", filename)
+		}
+	}()
+
+	header := make([]byte, mimeTypeHeaderBytes)
+
+	headerSize, err := io.ReadFull(file, header)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", 0, fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+
+	return mimetype.Detect(header[:headerSize]).String(), stat.Size(), nil
+}
+
+// resolvePageResources places the resources next to the built page, so they share its directory
+func (s *Impl) resolvePageResources(page Page) {
+	pageFileInfo := page.FileInfo()
+
+	outputDir := path.Dir(filepath.ToSlash(s.pageBuildFilename(pageFileInfo)))
+	if outputDir == "." {
+		outputDir = ""
+	}
+
+	for _, resource := range pageFileInfo.Resources {
+		resource.Output = path.Join(outputDir, resource.Name)
+		resource.Uri = "/" + strings.TrimPrefix(resource.Output, "/")
+	}
+}
@@ -0,0 +1,163 @@
+package stagen
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/stagens/stagen/pkg/template_engine"
+)
+
+const (
+	summaryWordsCount     = 70
+	readingWordsPerMinute = 200
+)
+
+// PageContent is the content rendered for the page text
+type PageContent struct {
+	Result *ThemeRenderResult
+	Err    error
+}
+
+type PageText struct {
+	Summary     string
+	Plain       string
+	WordCount   int
+	ReadingTime int
+	Toc         []TocHeading
+}
+
+func NewPageText(content string, tocMinLevel int, tocMaxLevel int) PageText {
+	plain := htmlToPlain(content)
+	words := strings.Fields(plain)
+
+	return PageText{
+		Summary:     pageSummary(content, words),
+		Plain:       plain,
+		WordCount:   len(words),
+		ReadingTime: int(math.Ceil(float64(len(words)) / readingWordsPerMinute)),
+		Toc:         tocHeadings(content, tocMinLevel, tocMaxLevel),
+	}
+}
+
+func (s *Impl) loadPagesTexts(ctx context.Context) error {
+	s.log.GetLogger(ctx).Info("Loading pages texts...")
+
+	settings := s.config.Settings()
+
+	// the texts are rendered with the data of the loaded pages, the build gets the data with the texts
+	s.resetPagesData()
+
+	pagesTexts := make(map[string]*PageText, len(s.pages))
+	pagesContents := make(map[string]*PageContent, len(s.pages))
+	pagesTextsMutex := sync.Mutex{}
+
+	err := s.forEachPage(ctx, s.sortedPages(), func(page Page) error {
+		contentResult, err := s.renderPageText(ctx, page)
+
+		pagesTextsMutex.Lock()
+		defer pagesTextsMutex.Unlock()
+
+		pagesContents[page.Id()] = &PageContent{
+			Result: contentResult,
+			Err:    err,
+		}
+
+		if err != nil {
+			return nil
+		}
+
+		pageText := NewPageText(string(contentResult.Content), settings.TocMinLevel(), settings.TocMaxLevel())
+
+		pagesTexts[page.Id()] = &pageText
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.pagesTexts = pagesTexts
+	s.pagesContents = pagesContents
+
+	s.resetPagesData()
+
+	return nil
+}
+
+// renderPageText renders the page content without the layout,
+// the build reuses the content or reports the error with the page
+func (s *Impl) renderPageText(ctx context.Context, page Page) (*ThemeRenderResult, error) {
+	pageConfig := page.Config()
+	pageFileInfo := page.FileInfo()
+
+	theme, ok := s.themes[pageConfig.Theme()]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s for page '%s'", ErrThemeNotFound, pageConfig.Theme(), page.Id())
+	}
+
+	data, err := s.getTemplateData(ctx, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template data for page '%s': %w", page.Id(), err)
+	}
+
+	contentResult, err := theme.RenderContent(
+		ctx,
+		pageConfig.Imports(),
+		template_engine.Source{
+			File:    pageFileInfo.SourceFilename,
+			Line:    pageFileInfo.ContentLine,
+			Content: string(page.Content()),
+		},
+		pageFileInfo.IsMarkdown,
+		data,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render content of page '%s': %w", page.Id(), err)
+	}
+
+	return contentResult, nil
+}
+
+func (s *Impl) pageText(pageId string) PageText {
+	pageText, ok := s.pagesTexts[pageId]
+	if !ok {
+		return PageText{
+			Summary:     "",
+			Plain:       "",
+			WordCount:   0,
+			ReadingTime: 0,
+			Toc:         make([]TocHeading, 0),
+		}
+	}
+
+	return *pageText
+}
+
+// pageSummary is the content up to the more marker,
+// or the first paragraph (or words) when there is no marker
+func pageSummary(content string, words []string) string {
+	if location := summaryMarkerRegexp.FindStringIndex(content); location != nil {
+		return strings.TrimSpace(content[:location[0]])
+	}
+
+	if paragraph := firstParagraphRegexp.FindString(content); paragraph != "" {
+		return paragraph
+	}
+
+	if len(words) > summaryWordsCount {
+		return html.EscapeString(strings.Join(words[:summaryWordsCount], " ")) + "…"
+	}
+
+	return html.EscapeString(strings.Join(words, " "))
+}
+
+func htmlToPlain(content string) string {
+	content = summaryMarkerRegexp.ReplaceAllString(content, "")
+	content = htmlTagRegexp.ReplaceAllString(content, " ")
+
+	return strings.Join(strings.Fields(html.UnescapeString(content)), " ")
+}
@@ -0,0 +1,160 @@
+package stagen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/djherbis/times"
+	"github.com/pixality-inc/golang-core/storage"
+)
+
+const (
+	pageDateVariable    = "date"
+	pageLastModVariable = "lastmod"
+
+	pagePublishDateVariable = "publish_date"
+	pageExpiryDateVariable  = "expiry_date"
+)
+
+var (
+	ErrUnknownPageTimeSource = errors.New("unknown page time source")
+	ErrInvalidPageTime       = errors.New("invalid page time")
+)
+
+var pageTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	time.DateOnly,
+}
+
+type FsTimeSpecImpl struct {
+	stat times.Timespec
+}
+
+func NewFsTimeSpec(stat times.Timespec) *FsTimeSpecImpl {
+	return &FsTimeSpecImpl{
+		stat: stat,
+	}
+}
+
+func (t *FsTimeSpecImpl) ModTime() time.Time {
+	return t.stat.ModTime()
+}
+
+func (t *FsTimeSpecImpl) AccessTime() time.Time {
+	return t.stat.AccessTime()
+}
+
+func (t *FsTimeSpecImpl) ChangeTime() time.Time {
+	if t.stat.HasChangeTime() {
+		return t.stat.ChangeTime()
+	}
+
+	return t.stat.ModTime()
+}
+
+func (t *FsTimeSpecImpl) BirthTime() time.Time {
+	if t.stat.HasBirthTime() {
+		return t.stat.BirthTime()
+	}
+
+	return t.ChangeTime()
+}
+
+func (t *FsTimeSpecImpl) HasChangeTime() bool {
+	return true
+}
+
+func (t *FsTimeSpecImpl) HasBirthTime() bool {
+	return true
+}
+
+func (s *Impl) getPageTimeSpec(ctx context.Context, pageFilename string) (times.Timespec, error) {
+	switch pageTimeSource := s.config.Settings().PageTimeSource(); pageTimeSource {
+	case PageTimeSourceFs:
+		// @todo!!!!
+		localStorage, ok := s.storage.(storage.LocalStorage)
+		if !ok {
+			return nil, ErrStorageIsNotALocalStorage
+		}
+
+		localFilename, err := localStorage.LocalPath(ctx, pageFilename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get local file path: %w", err)
+		}
+
+		stat, err := times.Stat(localFilename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat page file '%s': %w", pageFilename, err)
+		}
+
+		return NewFsTimeSpec(stat), nil
+
+	case PageTimeSourceFrontMatter:
+		return NewFakeTimeSpec(s.buildTime), nil
+
+	case PageTimeSourceClock:
+		return NewFakeTimeSpec(s.clock.Now()), nil
+
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPageTimeSource, pageTimeSource)
+	}
+}
+
+func (s *Impl) applyPageTimes(pageFileInfo *PageFileInfo, variables map[string]any) error {
+	if s.config.Settings().PageTimeSource() == PageTimeSourceClock {
+		return nil
+	}
+
+	date, hasDate, err := parsePageTime(variables[pageDateVariable])
+	if err != nil {
+		return fmt.Errorf("failed to parse '%s': %w", pageDateVariable, err)
+	}
+
+	lastMod, hasLastMod, err := parsePageTime(variables[pageLastModVariable])
+	if err != nil {
+		return fmt.Errorf("failed to parse '%s': %w", pageLastModVariable, err)
+	}
+
+	if !hasLastMod && hasDate && s.config.Settings().PageTimeSource() == PageTimeSourceFrontMatter {
+		lastMod, hasLastMod = date, true
+	}
+
+	if hasDate {
+		pageFileInfo.CreatedAt = date
+	}
+
+	if hasLastMod {
+		pageFileInfo.ModifiedAt = lastMod
+		pageFileInfo.ChangedAt = lastMod
+	}
+
+	return nil
+}
+
+func parsePageTime(value any) (time.Time, bool, error) {
+	switch typedValue := value.(type) {
+	case nil:
+		return time.Time{}, false, nil
+
+	case time.Time:
+		return typedValue, true, nil
+
+	case string:
+		for _, layout := range pageTimeLayouts {
+			if parsedTime, err := time.Parse(layout, typedValue); err == nil {
+				return parsedTime, true, nil
+			}
+		}
+
+		return time.Time{}, false, fmt.Errorf("%w: %s", ErrInvalidPageTime, typedValue)
+
+	default:
+		return time.Time{}, false, fmt.Errorf("%w: %v", ErrInvalidPageTime, value)
+	}
+}
@@ -0,0 +1,86 @@
+package stagen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStat struct {
+	modTime    time.Time
+	changeTime time.Time
+	birthTime  time.Time
+}
+
+func (s *fakeStat) ModTime() time.Time {
+	return s.modTime
+}
+
+func (s *fakeStat) AccessTime() time.Time {
+	return s.modTime
+}
+
+func (s *fakeStat) ChangeTime() time.Time {
+	return s.changeTime
+}
+
+func (s *fakeStat) BirthTime() time.Time {
+	return s.birthTime
+}
+
+func (s *fakeStat) HasChangeTime() bool {
+	return !s.changeTime.IsZero()
+}
+
+func (s *fakeStat) HasBirthTime() bool {
+	return !s.birthTime.IsZero()
+}
+
+func TestFsTimeSpec(t *testing.T) {
+	t.Parallel()
+
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	changeTime := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	birthTime := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		stat       *fakeStat
+		changeTime time.Time
+		birthTime  time.Time
+	}{
+		{
+			name:       "birth_time",
+			stat:       &fakeStat{modTime: modTime, changeTime: changeTime, birthTime: birthTime},
+			changeTime: changeTime,
+			birthTime:  birthTime,
+		},
+		{
+			name:       "no_birth_time",
+			stat:       &fakeStat{modTime: modTime, changeTime: changeTime, birthTime: time.Time{}},
+			changeTime: changeTime,
+			birthTime:  changeTime,
+		},
+		{
+			name:       "no_change_time",
+			stat:       &fakeStat{modTime: modTime, changeTime: time.Time{}, birthTime: time.Time{}},
+			changeTime: modTime,
+			birthTime:  modTime,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			timeSpec := NewFsTimeSpec(testCase.stat)
+
+			require.True(t, timeSpec.HasChangeTime())
+			require.True(t, timeSpec.HasBirthTime())
+			require.Equal(t, modTime, timeSpec.ModTime())
+			require.Equal(t, testCase.changeTime, timeSpec.ChangeTime())
+			require.Equal(t, testCase.birthTime, timeSpec.BirthTime())
+		})
+	}
+}
@@ -6,12 +6,15 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/adrg/frontmatter"
 	"gopkg.in/yaml.v3"
 
 	"github.com/stagens/stagen/pkg/filetree"
+	"github.com/stagens/stagen/pkg/source_error"
 )
 
 const (
@@ -35,7 +38,9 @@ func (s *Impl) loadPages(ctx context.Context) error {
 		return fmt.Errorf("failed to build tree for pages dir: %w", err)
 	}
 
-	if err = s.processPagesDirEntry(ctx, tree, nil); err != nil {
+	passthrough := newPassthroughPatterns("", s.config.Settings().Passthrough())
+
+	if err = s.processPagesDirEntry(ctx, tree, nil, passthrough); err != nil {
 		return fmt.Errorf("failed to process pages dir entries: %w", err)
 	}
 
@@ -46,6 +51,7 @@ func (s *Impl) loadPage(
 	ctx context.Context,
 	pageFilename string,
 	dirConfigs []PageConfig,
+	resourcesFilenames []string,
 ) error {
 	if pageFilename == "" {
 		return ErrNoName
@@ -55,16 +61,27 @@ func (s *Impl) loadPage(
 
 	log.Infof("Loading page '%s'...", pageFilename)
 
-	pageFileInfo, err := s.getPageFileInfo(pageFilename)
+	pageFileInfo, err := s.getPageFileInfo(ctx, pageFilename)
 	if err != nil {
 		return fmt.Errorf("failed to get page '%s' file info: %w", pageFilename, err)
 	}
 
+	pageFileInfo.Resources, err = s.loadPageResources(ctx, pageFilename, resourcesFilenames)
+	if err != nil {
+		return fmt.Errorf("failed to load page '%s' resources: %w", pageFilename, err)
+	}
+
 	fileContent, err := s.readFile(ctx, pageFilename)
 	if err != nil {
 		return fmt.Errorf("failed to read file '%s': %w", pageFilename, err)
 	}
 
+	if isBinaryContent(fileContent) {
+		s.addPassthroughFile(ctx, pageFilename)
+
+		return nil
+	}
+
 	page, err := s.createPage(
 		ctx,
 		pageFileInfo,
@@ -76,7 +93,9 @@ func (s *Impl) loadPage(
 		return fmt.Errorf("failed to create page '%s': %w", pageFilename, err)
 	}
 
-	if err = s.addPage(page); err != nil {
+	s.resolvePageResources(page)
+
+	if err = s.addPage(ctx, page); err != nil {
 		return fmt.Errorf("failed to add page '%s': %w", pageFilename, err)
 	}
 
@@ -102,12 +121,17 @@ func (s *Impl) createPage(
 
 	content, err = frontmatter.Parse(bytes.NewReader(pageContent), &pageVariables)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse file front matter: %w", err)
+		return nil, fmt.Errorf("failed to parse file front matter: %w", frontMatterError(pageFileInfo, pageContent, err))
 	}
 
 	_, err = frontmatter.Parse(bytes.NewReader(pageContent), &pageConfigYaml)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse file front matter: %w", err)
+		return nil, fmt.Errorf("failed to parse file front matter: %w", frontMatterError(pageFileInfo, pageContent, err))
+	}
+
+	// the front matter is cut off, so the lines of the content are shifted
+	if bytes.HasSuffix(pageContent, content) {
+		pageFileInfo.ContentLine = bytes.Count(pageContent[:len(pageContent)-len(content)], []byte("\n")) + 1
 	}
 
 	if pageVariables == nil {
@@ -118,6 +142,10 @@ func (s *Impl) createPage(
 		pageVariables[k] = v //nolint:modernize // @todo
 	}
 
+	if err = s.applyPageTimes(pageFileInfo, pageVariables); err != nil {
+		return nil, fmt.Errorf("failed to apply page times: %w", err)
+	}
+
 	if pageConfigYaml == nil {
 		readPageConfig = NewDefaultPageConfig("empty", pageVariables)
 	} else {
@@ -145,6 +173,18 @@ func (s *Impl) createPage(
 
 	pageName := filepath.Join(pageFileInfo.PathWithoutWorkDirAndPagesDir, pageFileInfo.FilenameWithoutExtension)
 
+	language, languagePageName, translationKey := s.pageLanguage(pageFileInfo, pageName)
+
+	pageFileInfo.TranslationKey = translationKey
+
+	// pages out of the language dir are moved under the language prefix
+	if languagePageName != filepath.ToSlash(pageName) {
+		pageFileInfo.OutputFilename = filepath.Join(language.Prefix, s.pageBuildFilename(pageFileInfo))
+		pageName = filepath.FromSlash(languagePageName)
+	}
+
+	pageFileInfo.Lang = language.Code
+
 	pageId := pageName
 	if pageId == "index" {
 		pageId = ""
@@ -182,6 +222,31 @@ func (s *Impl) createPage(
 		pageUri = "/"
 	}
 
+	if s.config.Settings().PrettyUrls() {
+		switch pageFileInfo.FileExtension {
+		case htmlExtension, markdownExtension:
+			prettyUri := pageName
+			if prettyUri == "index" {
+				prettyUri = ""
+			}
+
+			prettyUri, _ = strings.CutSuffix(prettyUri, "/index")
+
+			pageUri, pageFileInfo.OutputFilename = s.uriPaths(prettyUri+"/", htmlExtension)
+		}
+	}
+
+	if permalink := pageConfig.Permalink(); permalink != "" {
+		if language.Prefix != "" {
+			permalink = "/" + language.Prefix + "/" + strings.TrimPrefix(permalink, "/")
+		}
+
+		pageUri, pageFileInfo.OutputFilename, err = s.permalinkPaths(pageFileInfo, permalink, pageConfig.Variables())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	page := NewPage(
 		pageId,
 		pageName,
@@ -194,9 +259,20 @@ func (s *Impl) createPage(
 	return page, nil
 }
 
-func (s *Impl) addPage(page Page) error {
+func (s *Impl) addPage(ctx context.Context, page Page) error {
 	pageId := page.Id()
 
+	skipReason, err := s.pageSkipReason(page)
+	if err != nil {
+		return err
+	}
+
+	if skipReason != "" {
+		s.log.GetLogger(ctx).Infof("Skipping %s page '%s'", skipReason, pageId)
+
+		return nil
+	}
+
 	if _, ok := s.pages[pageId]; ok {
 		return fmt.Errorf("%w: %s", ErrPageAlreadyExists, pageId)
 	}
@@ -206,21 +282,68 @@ func (s *Impl) addPage(page Page) error {
 	return nil
 }
 
-func (s *Impl) getPageFileInfo(pageFilename string) (*PageFileInfo, error) {
-	// @todo!!!!
-	// stat, err := times.Stat(pageFilename)
-	// if err != nil {
-	// 	return nil, fmt.Errorf("failed to stat page file '%s': %w", pageFilename, err)
-	// }
-	stat := NewFakeTimeSpec(s.clock.Now())
+// buildDrafts is decided by the command, not by the env: watch and dev turn it on, build needs --drafts
+func (s *Impl) buildDrafts() bool {
+	return s.config.Settings().BuildDrafts()
+}
+
+func (s *Impl) pageSkipReason(page Page) (string, error) {
+	pageConfig := page.Config()
+
+	if pageConfig.IsDraft() && !s.buildDrafts() {
+		return "draft", nil
+	}
+
+	pageVariables := pageConfig.Variables()
+
+	publishDate, hasPublishDate, err := parsePageTime(pageVariables[pagePublishDateVariable])
+	if err != nil {
+		return "", fmt.Errorf("failed to parse '%s': %w", pagePublishDateVariable, err)
+	}
+
+	if hasPublishDate && publishDate.After(s.buildTime) && !s.config.Settings().BuildFuture() {
+		return "future", nil
+	}
+
+	expiryDate, hasExpiryDate, err := parsePageTime(pageVariables[pageExpiryDateVariable])
+	if err != nil {
+		return "", fmt.Errorf("failed to parse '%s': %w", pageExpiryDateVariable, err)
+	}
+
+	if hasExpiryDate && !expiryDate.After(s.buildTime) {
+		return "expired", nil
+	}
+
+	return "", nil
+}
+
+func (s *Impl) isListedPage(page Page) bool {
+	pageConfig := page.Config()
+
+	return !pageConfig.IsHidden() && !pageConfig.IsSystem()
+}
+
+func (s *Impl) getPageFileInfo(ctx context.Context, pageFilename string) (*PageFileInfo, error) {
+	stat, err := s.getPageTimeSpec(ctx, pageFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	// the language suffix is not a part of the page name nor of its extension
+	langPageFilename, lang := s.splitPageLang(pageFilename)
 
 	pageFileInfo := NewPageFileInfo(
-		pageFilename,
+		langPageFilename,
 		s.workDir,
 		s.pagesDir(),
 		stat,
 	)
 
+	pageFileInfo.Filename = pageFilename
+	pageFileInfo.SourceFilename = pageFilename
+	pageFileInfo.BaseFilename = filepath.Base(pageFilename)
+	pageFileInfo.Lang = lang
+
 	return pageFileInfo, nil
 }
 
@@ -228,12 +351,15 @@ func (s *Impl) processPagesDirEntry(
 	ctx context.Context,
 	dirEntry filetree.Entry,
 	dirConfigs []PageConfig,
+	passthrough []PassthroughPattern,
 ) error {
 	dir := filepath.Join(dirEntry.Path(), dirEntry.Name())
 
-	entryDirConfigs, err := s.readDirConfigs(ctx, dir)
+	entryDirConfigs, entryPassthrough, err := s.readDirConfigs(ctx, dir)
 	if err != nil {
-		return fmt.Errorf("failed to read dir config: %w", err)
+		if err = s.collectProblem(dir, fmt.Errorf("failed to read dir config: %w", err)); err != nil {
+			return err
+		}
 	}
 
 	childDirConfigs := make([]PageConfig, 0, len(dirConfigs)+len(entryDirConfigs))
@@ -241,7 +367,30 @@ func (s *Impl) processPagesDirEntry(
 	childDirConfigs = append(childDirConfigs, dirConfigs...)
 	childDirConfigs = append(childDirConfigs, entryDirConfigs...)
 
-	if err = s.processPagesDirEntries(ctx, dirEntry.Children(), childDirConfigs); err != nil {
+	childPassthrough := slices.Concat(passthrough, entryPassthrough)
+
+	sectionTitle := ""
+
+	for _, entryDirConfig := range entryDirConfigs {
+		if entryDirConfig.Title() != "" {
+			sectionTitle = entryDirConfig.Title()
+		}
+	}
+
+	sectionId := s.pagesRelPath(dir)
+	if sectionId == "." {
+		sectionId = ""
+	}
+
+	s.addSection(sectionId, sectionTitle)
+
+	// the pages dir itself is a section, not a bundle
+	bundleIndex := ""
+	if dir != s.pagesDir() {
+		bundleIndex = bundleIndexFilename(dirEntry.Children())
+	}
+
+	if err = s.processPagesDirEntries(ctx, dirEntry.Children(), childDirConfigs, childPassthrough, bundleIndex); err != nil {
 		return err
 	}
 
@@ -252,12 +401,31 @@ func (s *Impl) processPagesDirEntries(
 	ctx context.Context,
 	entries []filetree.Entry,
 	dirConfigs []PageConfig,
+	passthrough []PassthroughPattern,
+	bundleIndex string,
 ) error {
 	log := s.log.GetLogger(ctx)
 
+	// files of a bundle which are not pages are resources of its index page
+	resourcesFilenames := make([]string, 0)
+
+	if bundleIndex != "" {
+		for _, dirEntry := range entries {
+			if dirEntry.IsDir() || pageIgnoreFilenameRegexp.MatchString(dirEntry.Name()) || isPageFilename(dirEntry.Name()) {
+				continue
+			}
+
+			filename := filepath.Join(dirEntry.Path(), dirEntry.Name())
+
+			if !s.isPassthroughFile(filename, passthrough) {
+				resourcesFilenames = append(resourcesFilenames, filename)
+			}
+		}
+	}
+
 	for _, dirEntry := range entries {
 		if dirEntry.IsDir() {
-			if err := s.processPagesDirEntry(ctx, dirEntry, dirConfigs); err != nil {
+			if err := s.processPagesDirEntry(ctx, dirEntry, dirConfigs, passthrough); err != nil {
 				return err
 			}
 
@@ -272,16 +440,36 @@ func (s *Impl) processPagesDirEntries(
 			continue
 		}
 
-		if err := s.loadPage(ctx, pageFilename, dirConfigs); err != nil {
-			return fmt.Errorf("%w: %s: %w", ErrLoadPage, pageFilename, err)
+		if s.isPassthroughFile(pageFilename, passthrough) {
+			s.addPassthroughFile(ctx, pageFilename)
+
+			continue
+		}
+
+		if bundleIndex != "" && !isPageFilename(dirEntry.Name()) {
+			continue
+		}
+
+		var pageResourcesFilenames []string
+		if pageFilename == bundleIndex {
+			pageResourcesFilenames = resourcesFilenames
+		}
+
+		if err := s.loadPage(ctx, pageFilename, dirConfigs, pageResourcesFilenames); err != nil {
+			err = fmt.Errorf("%w: %s: %w", ErrLoadPage, pageFilename, err)
+
+			if err = s.collectProblem(pageFilename, err); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
-func (s *Impl) readDirConfigs(ctx context.Context, dir string) ([]PageConfig, error) {
+func (s *Impl) readDirConfigs(ctx context.Context, dir string) ([]PageConfig, []PassthroughPattern, error) {
 	dirConfigs := make([]PageConfig, 0)
+	passthrough := make([]PassthroughPattern, 0)
 
 	configFiles := s.getPossibleConfigFilenames()
 
@@ -289,20 +477,21 @@ func (s *Impl) readDirConfigs(ctx context.Context, dir string) ([]PageConfig, er
 		configFilePath := filepath.Join(dir, configFilename)
 
 		if exists, err := s.storage.FileExists(ctx, configFilePath); err != nil {
-			return nil, fmt.Errorf("faile to check if file %s exists: %w", configFilePath, err)
+			return nil, nil, fmt.Errorf("faile to check if file %s exists: %w", configFilePath, err)
 		} else if !exists {
 			continue
 		}
 
 		dirConfig, err := s.readDirConfig(ctx, configFilePath)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read dir %s config %s: %w", dir, configFilePath, err)
+			return nil, nil, fmt.Errorf("failed to read dir %s config %s: %w", dir, configFilePath, err)
 		}
 
 		dirConfigs = append(dirConfigs, dirConfig.ToPageConfig(dir))
+		passthrough = append(passthrough, newPassthroughPatterns(s.pagesRelPath(dir), dirConfig.Passthrough())...)
 	}
 
-	return dirConfigs, nil
+	return dirConfigs, passthrough, nil
 }
 
 func (s *Impl) readDirConfig(ctx context.Context, filename string) (DirConfig, error) {
@@ -319,3 +508,23 @@ func (s *Impl) readDirConfig(ctx context.Context, filename string) (DirConfig, e
 
 	return dirConfigYaml, nil
 }
+
+func frontMatterError(pageFileInfo *PageFileInfo, content []byte, err error) error {
+	matches := frontMatterErrorRegexp.FindStringSubmatch(err.Error())
+	if matches == nil {
+		return source_error.New(pageFileInfo.SourceFilename, 0, 0, err.Error(), "", err)
+	}
+
+	// the first line of the front matter is its opening delimiter
+	line, _ := strconv.Atoi(matches[1])
+	line++
+
+	return source_error.New(
+		pageFileInfo.SourceFilename,
+		line,
+		0,
+		matches[2],
+		source_error.Excerpt(string(content), 1, line, 0),
+		err,
+	)
+}
@@ -0,0 +1,311 @@
+package stagen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	pagePaginateVariable = "paginate"
+
+	paginateCollectionPages    = "Pages"
+	paginateCollectionChildren = "Children"
+)
+
+var (
+	ErrInvalidPaginate = errors.New("invalid paginate")
+	ErrLoadPaginator   = errors.New("load paginator")
+)
+
+type Paginator struct {
+	Items      []any
+	PageNumber int
+	PageSize   int
+	TotalItems int
+	TotalPages int
+	HasPrev    bool
+	PrevUri    string
+	PrevUrl    string
+	HasNext    bool
+	NextUri    string
+	NextUrl    string
+	Uris       []string
+	pagesIds   []string
+}
+
+// loadPaginators splits the collections of paginated pages,
+// the first page keeps its uri and the next ones are added as system pages
+func (s *Impl) loadPaginators(ctx context.Context) error {
+	s.log.GetLogger(ctx).Info("Loading paginators...")
+
+	s.paginators = make(map[string]*Paginator)
+
+	pagesFilenames := s.pagesFilenames()
+
+	for _, page := range s.sortedPages() {
+		paginateConfig, ok, err := pagePaginateConfig(page)
+		if err == nil && ok {
+			err = s.loadPaginator(ctx, page, paginateConfig, pagesFilenames)
+		}
+
+		if err != nil {
+			err = fmt.Errorf("%w: %s: %w", ErrLoadPaginator, page.Id(), err)
+
+			if err = s.collectProblem(page.FileInfo().Filename, err); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Impl) loadPaginator(ctx context.Context, page Page, paginateConfig PaginateConfig, pagesFilenames map[string]string) error {
+	pageSize := paginateConfig.Size()
+	if pageSize <= 0 {
+		return fmt.Errorf("%w: size must be positive: %d", ErrInvalidPaginate, pageSize)
+	}
+
+	items, err := s.paginateCollection(page, paginateConfig.Collection())
+	if err != nil {
+		return err
+	}
+
+	// the pages collections hold page ids, the template gets the page data of them
+	var pagesIds []string
+
+	if collection := paginateConfig.Collection(); collection == paginateCollectionPages || collection == paginateCollectionChildren {
+		pagesIds = make([]string, len(items))
+
+		for index, item := range items {
+			pagesIds[index], _ = item.(string)
+		}
+	}
+
+	totalPages := max(1, (len(items)+pageSize-1)/pageSize)
+
+	s.log.GetLogger(ctx).Infof("Paginating page '%s' into %d pages...", page.Id(), totalPages)
+
+	pagers := make([]Page, 0, totalPages)
+	uris := make([]string, 0, totalPages)
+
+	for pageNumber := 1; pageNumber <= totalPages; pageNumber++ {
+		pager := page
+		if pageNumber > 1 {
+			pager = s.newPagerPage(page, pageNumber)
+		}
+
+		pagers = append(pagers, pager)
+		uris = append(uris, pager.Uri())
+	}
+
+	for index, pager := range pagers {
+		start := index * pageSize
+		end := min(start+pageSize, len(items))
+
+		paginator := &Paginator{
+			Items:      items[start:end],
+			PageNumber: index + 1,
+			PageSize:   pageSize,
+			TotalItems: len(items),
+			TotalPages: totalPages,
+			HasPrev:    index > 0,
+			PrevUri:    "",
+			PrevUrl:    "",
+			HasNext:    index < totalPages-1,
+			NextUri:    "",
+			NextUrl:    "",
+			Uris:       uris,
+			pagesIds:   nil,
+		}
+
+		if pagesIds != nil {
+			paginator.pagesIds = pagesIds[start:end]
+		}
+
+		if paginator.HasPrev {
+			paginator.PrevUri = uris[index-1]
+
+			if paginator.PrevUrl, err = url.JoinPath(s.siteConfig.BaseUrl(), paginator.PrevUri); err != nil {
+				return fmt.Errorf("failed to resolve page %d url: %w", index, err)
+			}
+		}
+
+		if paginator.HasNext {
+			paginator.NextUri = uris[index+1]
+
+			if paginator.NextUrl, err = url.JoinPath(s.siteConfig.BaseUrl(), paginator.NextUri); err != nil {
+				return fmt.Errorf("failed to resolve page %d url: %w", index+2, err)
+			}
+		}
+
+		s.paginators[pager.Id()] = paginator
+
+		if index == 0 {
+			continue
+		}
+
+		// the pager output is a directory index, a page of another id may be built there too
+		pagerFilename := s.pageBuildFilename(pager.FileInfo())
+
+		if pageId, ok := pagesFilenames[pagerFilename]; ok {
+			return fmt.Errorf("%w: page %d is built by page '%s'", ErrPageAlreadyExists, index+1, pageId)
+		}
+
+		if err = s.addPage(ctx, pager); err != nil {
+			return fmt.Errorf("failed to add page %d: %w", index+1, err)
+		}
+
+		pagesFilenames[pagerFilename] = pager.Id()
+	}
+
+	return nil
+}
+
+// newPagerPage is a copy of the page at <page>/page/<number>/
+func (s *Impl) newPagerPage(page Page, pageNumber int) Page {
+	pageName := filepath.ToSlash(page.Name())
+
+	pagerBase := pageName
+	if path.Base(pageName) == "index" {
+		pagerBase = parentSectionId(pageName)
+	}
+
+	pagerName := path.Join(pagerBase, "page", strconv.Itoa(pageNumber))
+
+	pagerFileInfo := *page.FileInfo()
+
+	pagerUri, outputFilename := s.uriPaths(pagerName+"/", htmlExtension)
+
+	pagerFileInfo.OutputFilename = outputFilename
+
+	pagerConfig := MergePageConfigs(
+		page.Config(),
+		NewPageConfig("paginator", "", "", "", false, false, true, "", nil, nil, nil, nil),
+	)
+
+	return NewPage(
+		pagerName,
+		pagerName,
+		pagerUri,
+		&pagerFileInfo,
+		page.Content(),
+		pagerConfig,
+	)
+}
+
+func (s *Impl) paginateCollection(page Page, collection string) ([]any, error) {
+	switch collection {
+	case "":
+		return nil, fmt.Errorf("%w: no collection", ErrInvalidPaginate)
+
+	case paginateCollectionPages:
+		items := make([]any, 0, len(s.pages))
+
+		for _, collectionPage := range sortPagesByWeight(s.sortedPages()) {
+			if s.isListedPage(collectionPage) {
+				items = append(items, collectionPage.Id())
+			}
+		}
+
+		return items, nil
+
+	case paginateCollectionChildren:
+		children := s.pageRelations(page).Children
+
+		items := make([]any, len(children))
+		for index, child := range children {
+			items[index] = child
+		}
+
+		return items, nil
+
+	default:
+		switch value := page.Config().Variables()[collection].(type) {
+		case []any:
+			return value, nil
+
+		case []string:
+			items := make([]any, len(value))
+			for index, item := range value {
+				items[index] = item
+			}
+
+			return items, nil
+
+		default:
+			return nil, fmt.Errorf("%w: collection '%s' is not a list: %T", ErrInvalidPaginate, collection, value)
+		}
+	}
+}
+
+// paginatorData is the paginator of the page, the items of a pages collection are the page data of Pages
+func (s *Impl) paginatorData(pageId string, pagesData map[string]any) *Paginator {
+	paginator, ok := s.paginators[pageId]
+	if !ok || paginator.pagesIds == nil {
+		return paginator
+	}
+
+	result := *paginator
+
+	result.Items = make([]any, 0, len(paginator.pagesIds))
+
+	for _, itemPageId := range paginator.pagesIds {
+		if itemPageData, ok := pagesData[itemPageId]; ok {
+			result.Items = append(result.Items, itemPageData)
+		}
+	}
+
+	return &result
+}
+
+// paginatorsEqual compares the paginators of a page, the items come from the front matter and are compared deeply
+func paginatorsEqual(a *Paginator, b *Paginator) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.PageNumber == b.PageNumber &&
+		a.PageSize == b.PageSize &&
+		a.TotalItems == b.TotalItems &&
+		a.TotalPages == b.TotalPages &&
+		a.HasPrev == b.HasPrev &&
+		a.PrevUri == b.PrevUri &&
+		a.PrevUrl == b.PrevUrl &&
+		a.HasNext == b.HasNext &&
+		a.NextUri == b.NextUri &&
+		a.NextUrl == b.NextUrl &&
+		slices.Equal(a.Uris, b.Uris) &&
+		slices.Equal(a.pagesIds, b.pagesIds) &&
+		reflect.DeepEqual(a.Items, b.Items)
+}
+
+func pagePaginateConfig(page Page) (PaginateConfig, bool, error) {
+	value, ok := page.Config().Variables()[pagePaginateVariable]
+	if !ok || value == nil {
+		return nil, false, nil
+	}
+
+	// front matter maps are not typed, so the value goes through yaml again
+	content, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %w", ErrInvalidPaginate, err)
+	}
+
+	var paginateConfig PaginateConfigYaml
+
+	if err = yaml.Unmarshal(content, &paginateConfig); err != nil {
+		return nil, false, fmt.Errorf("%w: %w", ErrInvalidPaginate, err)
+	}
+
+	return &paginateConfig, true, nil
+}
@@ -0,0 +1,117 @@
+package stagen
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+type PassthroughPattern struct {
+	Dir     string
+	Pattern string
+}
+
+func newPassthroughPatterns(dir string, patterns []string) []PassthroughPattern {
+	if dir == "." {
+		dir = ""
+	}
+
+	result := make([]PassthroughPattern, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		result = append(result, PassthroughPattern{
+			Dir:     dir,
+			Pattern: pattern,
+		})
+	}
+
+	return result
+}
+
+// Match matches patterns without a slash against the file name and others against the path relative to the pattern dir
+func (p PassthroughPattern) Match(filename string) bool {
+	name := path.Base(filename)
+
+	if strings.Contains(p.Pattern, "/") {
+		if p.Dir != "" && !strings.HasPrefix(filename, p.Dir+"/") {
+			return false
+		}
+
+		name = strings.TrimPrefix(filename, p.Dir+"/")
+	} else if p.Dir != "" && !strings.HasPrefix(filename, p.Dir+"/") {
+		return false
+	}
+
+	matched, err := path.Match(strings.TrimPrefix(p.Pattern, "/"), name)
+
+	return err == nil && matched
+}
+
+func (s *Impl) isPassthroughFile(filename string, patterns []PassthroughPattern) bool {
+	relFilename := s.pagesRelPath(filename)
+
+	for _, pattern := range patterns {
+		if pattern.Match(relFilename) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Impl) pagesRelPath(filename string) string {
+	relFilename, err := filepath.Rel(s.pagesDir(), filename)
+	if err != nil {
+		return filename
+	}
+
+	return filepath.ToSlash(relFilename)
+}
+
+// isBinaryContent tells if the content has a known mime type which is not text, such files have no front matter
+func isBinaryContent(content []byte) bool {
+	for mimeType := mimetype.Detect(content); mimeType != nil; mimeType = mimeType.Parent() {
+		if mimeType.Is("text/plain") {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Impl) addPassthroughFile(ctx context.Context, filename string) {
+	s.log.GetLogger(ctx).Infof("Passing through '%s'...", filename)
+
+	s.passthroughFiles[s.pagesRelPath(filename)] = filename
+}
+
+// pagesCopiedFiles returns the files of pages copied verbatim, build filename to source filename
+func (s *Impl) pagesCopiedFiles() map[string]string {
+	copiedFiles := maps.Clone(s.passthroughFiles)
+
+	for _, page := range s.sortedPages() {
+		for _, resource := range page.FileInfo().Resources {
+			copiedFiles[resource.Output] = resource.Source
+		}
+	}
+
+	return copiedFiles
+}
+
+func (s *Impl) copyPagesFiles(ctx context.Context) error {
+	copiedFiles := s.pagesCopiedFiles()
+
+	for _, filename := range slices.Sorted(maps.Keys(copiedFiles)) {
+		if err := s.copyPublicFile(ctx, copiedFiles[filename], filename); err != nil {
+			return fmt.Errorf("failed to copy file '%s': %w", copiedFiles[filename], err)
+		}
+	}
+
+	return nil
+}
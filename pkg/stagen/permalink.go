@@ -0,0 +1,142 @@
+package stagen
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+)
+
+const pageSlugVariable = "slug"
+
+var ErrPermalinkUnknownPlaceholder = errors.New("unknown permalink placeholder")
+
+// permalinkPaths expands the permalink pattern of the page and returns its uri and build filename
+func (s *Impl) permalinkPaths(
+	pageFileInfo *PageFileInfo,
+	permalink string,
+	variables map[string]any,
+) (string, string, error) {
+	var placeholderErr error
+
+	pageUri := permalinkPlaceholderRegexp.ReplaceAllStringFunc(permalink, func(placeholder string) string {
+		value, err := permalinkPlaceholderValue(pageFileInfo, strings.TrimPrefix(placeholder, ":"), variables)
+		if err != nil && placeholderErr == nil {
+			placeholderErr = err
+		}
+
+		return value
+	})
+	if placeholderErr != nil {
+		return "", "", placeholderErr
+	}
+
+	fileExt := pageFileInfo.FileExtension
+	if pageFileInfo.IsMarkdown {
+		fileExt = htmlExtension
+	}
+
+	pageUri, filename := s.uriPaths(pageUri, fileExt)
+
+	return pageUri, filename, nil
+}
+
+// uriPaths normalizes the uri and returns it with the build filename serving it,
+// a trailing slash (or any html page with pretty urls) is served by the index file of the directory
+func (s *Impl) uriPaths(uri string, fileExt string) (string, string) {
+	settings := s.config.Settings()
+
+	isDir := strings.HasSuffix(uri, "/")
+
+	uri = path.Clean("/" + uri)
+
+	if settings.PrettyUrls() && fileExt == htmlExtension && path.Ext(uri) == "" {
+		isDir = true
+	}
+
+	switch {
+	case isDir || uri == "/":
+		filename := path.Join(uri, "index"+fileExt)[1:]
+
+		if uri != "/" && settings.TrailingSlash() != TrailingSlashNever {
+			uri += "/"
+		}
+
+		return uri, filename
+
+	case path.Ext(uri) != "":
+		return uri, uri[1:]
+
+	default:
+		filename := uri[1:] + fileExt
+
+		if fileExt != htmlExtension || settings.UseUriHtmlFileExtension() {
+			uri += fileExt
+		}
+
+		return uri, filename
+	}
+}
+
+func permalinkPlaceholderValue(pageFileInfo *PageFileInfo, placeholder string, variables map[string]any) (string, error) {
+	switch placeholder {
+	case "year":
+		return fmt.Sprintf("%04d", pageFileInfo.CreatedAt.Year()), nil
+
+	case "month":
+		return fmt.Sprintf("%02d", int(pageFileInfo.CreatedAt.Month())), nil
+
+	case "day":
+		return fmt.Sprintf("%02d", pageFileInfo.CreatedAt.Day()), nil
+
+	case "filename":
+		return pageFileInfo.FilenameWithoutExtension, nil
+
+	case "section":
+		section, _, _ := strings.Cut(pageFileInfo.PathWithoutWorkDirAndPagesDir, "/")
+
+		return section, nil
+
+	case "slug":
+		if slug, ok := variables[pageSlugVariable].(string); ok && slug != "" {
+			return slug, nil
+		}
+
+		filename := pageFileInfo.FilenameWithoutExtension
+		if filename == "index" && pageFileInfo.PathWithoutWorkDirAndPagesDir != "" {
+			filename = path.Base(pageFileInfo.PathWithoutWorkDirAndPagesDir)
+		}
+
+		return slugify(filename), nil
+
+	default:
+		value, ok := variables[placeholder]
+		if !ok || value == nil {
+			return "", fmt.Errorf("%w: ':%s' in page '%s'", ErrPermalinkUnknownPlaceholder, placeholder, pageFileInfo.Filename)
+		}
+
+		return slugify(fmt.Sprint(value)), nil
+	}
+}
+
+func slugify(value string) string {
+	result := strings.Builder{}
+	dash := false
+
+	for _, char := range strings.ToLower(value) {
+		if unicode.IsLetter(char) || unicode.IsDigit(char) {
+			if dash && result.Len() > 0 {
+				result.WriteRune('-')
+			}
+
+			result.WriteRune(char)
+
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+
+	return result.String()
+}
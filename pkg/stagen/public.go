@@ -3,7 +3,9 @@ package stagen
 import (
 	"context"
 	"fmt"
+	"maps"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/pixality-inc/golang-core/storage"
@@ -16,13 +18,12 @@ func (s *Impl) publicDir() string {
 	return filepath.Join(s.workDir, "public")
 }
 
-// nolint:gocognit
 func (s *Impl) copyPublicFiles(ctx context.Context) error {
 	log := s.log.GetLogger(ctx)
 
 	log.Infof("Creating public dir...")
 
-	buildPublicDir := s.buildDir()
+	buildPublicDir := s.outputDir()
 
 	if err := s.storage.MkDir(ctx, buildPublicDir); err != nil {
 		return fmt.Errorf("failed to create public dir: %w", err)
@@ -30,14 +31,59 @@ func (s *Impl) copyPublicFiles(ctx context.Context) error {
 
 	log.Info("Copying public files...")
 
+	dirsToCopy, err := s.publicDirs(ctx)
+	if err != nil {
+		return err
+	}
+
+	// later public dirs override earlier ones
+	publicFiles := make(map[string]string)
+
+	for _, dir := range dirsToCopy {
+		log.Debugf("Collecting public files from '%s'...", dir)
+
+		tree, err := filetree.Tree(ctx, s.storage, dir, filetree.NoMaxLevel)
+		if err != nil {
+			return fmt.Errorf("failed to create tree for dir '%s': %w", dir, err)
+		}
+
+		err = filetree.Visit(ctx, tree, func(entry filetree.Entry) error {
+			if entry.IsDir() {
+				return nil
+			}
+
+			entryOriginalFilename := filepath.Join(entry.Path(), entry.Name())
+			entryFilename, _ := strings.CutPrefix(entryOriginalFilename, dir+"/")
+
+			publicFiles[entryFilename] = entryOriginalFilename
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to visit dir '%s': %w", dir, err)
+		}
+	}
+
+	for _, entryFilename := range slices.Sorted(maps.Keys(publicFiles)) {
+		if err = s.copyPublicFile(ctx, publicFiles[entryFilename], entryFilename); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Public files copied")
+
+	return nil
+}
+
+func (s *Impl) publicDirs(ctx context.Context) ([]string, error) {
 	dirsToCopy := make([]string, 0)
 
-	for _, theme := range s.themes {
+	for _, theme := range s.sortedThemes() {
 		themeDir := theme.Path()
 		themePublicDir := filepath.Join(themeDir, "public")
 
 		if exists, err := s.storage.FileExists(ctx, themePublicDir); err != nil {
-			return fmt.Errorf("faile to check if file %s exists: %w", themePublicDir, err)
+			return nil, fmt.Errorf("faile to check if file %s exists: %w", themePublicDir, err)
 		} else if !exists {
 			continue
 		}
@@ -45,12 +91,12 @@ func (s *Impl) copyPublicFiles(ctx context.Context) error {
 		dirsToCopy = append(dirsToCopy, themePublicDir)
 	}
 
-	for _, extension := range s.extensions {
+	for _, extension := range s.sortedExtensions() {
 		extensionDir := extension.Path()
 		extensionPublicDir := filepath.Join(extensionDir, "public")
 
 		if exists, err := s.storage.FileExists(ctx, extensionPublicDir); err != nil {
-			return fmt.Errorf("faile to check if file %s exists: %w", extensionPublicDir, err)
+			return nil, fmt.Errorf("faile to check if file %s exists: %w", extensionPublicDir, err)
 		} else if !exists {
 			continue
 		}
@@ -61,83 +107,64 @@ func (s *Impl) copyPublicFiles(ctx context.Context) error {
 	publicDir := s.publicDir()
 
 	if exists, err := s.storage.FileExists(ctx, publicDir); err != nil {
-		return fmt.Errorf("faile to check if file %s exists: %w", publicDir, err)
+		return nil, fmt.Errorf("faile to check if file %s exists: %w", publicDir, err)
 	} else if exists {
 		dirsToCopy = append(dirsToCopy, publicDir)
 	}
 
-	createdDirs := make(map[string]struct{})
-
-	for _, dir := range dirsToCopy {
-		log.Debugf("Copying public files from '%s'...", dir)
-
-		tree, err := filetree.Tree(ctx, s.storage, dir, filetree.NoMaxLevel)
-		if err != nil {
-			return fmt.Errorf("failed to create tree for dir '%s': %w", dir, err)
-		}
-
-		err = filetree.Visit(ctx, tree, func(entry filetree.Entry) error {
-			if entry.IsDir() {
-				entryDir := filepath.Join(entry.Path(), entry.Name())
-
-				entryDir, found := strings.CutPrefix(entryDir, dir+"/")
-				if !found {
-					entryDir, _ = strings.CutPrefix(entryDir, dir)
-				}
-
-				if entryDir != "" {
-					entryPublicDir := filepath.Join(buildPublicDir, entryDir)
+	return dirsToCopy, nil
+}
 
-					if _, exists := createdDirs[entryPublicDir]; exists {
-						return nil
-					}
+func (s *Impl) copyPublicFile(ctx context.Context, entryOriginalFilename string, entryFilename string) error {
+	log := s.log.GetLogger(ctx)
 
-					log.Debugf("Creating public directory '%s'...", entryPublicDir)
+	entryPublicFilename := filepath.Join(s.outputDir(), entryFilename)
 
-					if err = s.storage.MkDir(ctx, entryPublicDir); err != nil {
-						return fmt.Errorf("failed to create public dir '%s': %w", entryPublicDir, err)
-					}
+	hash, err := s.fileHash(ctx, entryOriginalFilename)
+	if err != nil {
+		return err
+	}
 
-					createdDirs[entryPublicDir] = struct{}{}
-				}
+	s.buildManifest.Set(entryFilename, hash)
 
-				return nil
-			}
+	s.report.AddPublicFile(BuildReportPublicFile{
+		Source: entryOriginalFilename,
+		Output: entryFilename,
+	})
 
-			entryOriginalFilename := filepath.Join(entry.Path(), entry.Name())
-			entryFilename, _ := strings.CutPrefix(entryOriginalFilename, dir+"/")
-			entryPublicFilename := filepath.Join(buildPublicDir, entryFilename)
+	if unchanged, err := s.reuseBuildFile(ctx, entryFilename, hash); err != nil {
+		return err
+	} else if unchanged {
+		log.Debugf("Public file '%s' is unchanged", entryFilename)
 
-			log.Debugf("Copying public file '%s' to '%s'", entryFilename, entryPublicFilename)
+		return nil
+	}
 
-			// @todo!!!!
-			localStorage, ok := s.storage.(storage.LocalStorage)
-			if !ok {
-				return ErrStorageIsNotALocalStorage
-			}
+	log.Debugf("Copying public file '%s' to '%s'", entryFilename, entryPublicFilename)
 
-			entrySourceLocalPath, err := localStorage.LocalPath(ctx, entryOriginalFilename)
-			if err != nil {
-				return fmt.Errorf("failed to get local file path: %w", err)
-			}
+	if err = s.createBuildDir(ctx, filepath.Dir(entryPublicFilename)); err != nil {
+		return err
+	}
 
-			entryDestLocalPath, err := localStorage.LocalPath(ctx, entryPublicFilename)
-			if err != nil {
-				return fmt.Errorf("failed to get local file path: %w", err)
-			}
+	// @todo!!!!
+	localStorage, ok := s.storage.(storage.LocalStorage)
+	if !ok {
+		return ErrStorageIsNotALocalStorage
+	}
 
-			if err = util.CopyFile(entrySourceLocalPath, entryDestLocalPath); err != nil {
-				return fmt.Errorf("failed to copy file '%s' to '%s': %w", entryOriginalFilename, entryPublicFilename, err)
-			}
+	entrySourceLocalPath, err := localStorage.LocalPath(ctx, entryOriginalFilename)
+	if err != nil {
+		return fmt.Errorf("failed to get local file path: %w", err)
+	}
 
-			return nil
-		})
-		if err != nil {
-			return fmt.Errorf("failed to visit dir '%s': %w", dir, err)
-		}
+	entryDestLocalPath, err := localStorage.LocalPath(ctx, entryPublicFilename)
+	if err != nil {
+		return fmt.Errorf("failed to get local file path: %w", err)
 	}
 
-	log.Infof("Public files copied")
+	if err = util.CopyFile(entrySourceLocalPath, entryDestLocalPath); err != nil {
+		return fmt.Errorf("failed to copy file '%s' to '%s': %w", entryOriginalFilename, entryPublicFilename, err)
+	}
 
 	return nil
 }
@@ -0,0 +1,414 @@
+package stagen
+
+import (
+	"context"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Section is a directory of the pages dir, the root section has an empty id
+type Section struct {
+	Id       string
+	Name     string
+	Title    string
+	PageId   string
+	HasPage  bool
+	Parent   string
+	Sections []*Section
+	Pages    []string
+}
+
+type PageNavigation struct {
+	Prev             string
+	HasPrev          bool
+	Next             string
+	HasNext          bool
+	PrevInSection    string
+	HasPrevInSection bool
+	NextInSection    string
+	HasNextInSection bool
+}
+
+type PageRelations struct {
+	Section   string
+	Parent    string
+	HasParent bool
+	Children  []string
+	Ancestors []string
+	PageNavigation
+}
+
+// sectionEntry is either a page or a section in a section
+type sectionEntry struct {
+	pageId  string
+	section *Section
+}
+
+func (s *Impl) addSection(sectionId string, title string) *Section {
+	if section, ok := s.sections[sectionId]; ok {
+		if title != "" {
+			section.Title = title
+		}
+
+		return section
+	}
+
+	if sectionId != "" {
+		s.addSection(parentSectionId(sectionId), "")
+	}
+
+	section := &Section{
+		Id:       sectionId,
+		Name:     path.Base(sectionId),
+		Title:    title,
+		PageId:   "",
+		HasPage:  false,
+		Parent:   parentSectionId(sectionId),
+		Sections: make([]*Section, 0),
+		Pages:    make([]string, 0),
+	}
+
+	if sectionId == "" {
+		section.Name = ""
+	}
+
+	s.sections[sectionId] = section
+
+	return section
+}
+
+func (s *Impl) loadSections(ctx context.Context) {
+	s.log.GetLogger(ctx).Info("Loading sections...")
+
+	s.addSection("", "")
+
+	for _, page := range s.orderedPages(ctx) {
+		if !s.isListedPage(page) {
+			continue
+		}
+
+		sectionId, isIndex := pageSection(page)
+
+		section := s.addSection(sectionId, "")
+
+		if isIndex {
+			section.PageId = page.Id()
+			section.HasPage = true
+
+			if section.Title == "" {
+				section.Title = page.Config().Title()
+			}
+		} else {
+			section.Pages = append(section.Pages, page.Id())
+		}
+	}
+
+	// children go before their parents, so empty sections are dropped bottom up
+	for _, sectionId := range slices.Backward(slices.Sorted(maps.Keys(s.sections))) {
+		section := s.sections[sectionId]
+
+		if section.Title == "" {
+			section.Title = section.Name
+		}
+
+		slices.SortFunc(section.Sections, func(a, b *Section) int {
+			return s.compareSections(a, b)
+		})
+
+		if sectionId == "" {
+			continue
+		}
+
+		if !section.HasPage && len(section.Pages) == 0 && len(section.Sections) == 0 {
+			delete(s.sections, sectionId)
+
+			continue
+		}
+
+		parent := s.sections[section.Parent]
+		parent.Sections = append(parent.Sections, section)
+	}
+
+	s.loadPagesNavigation()
+}
+
+// loadPagesNavigation links the pages in the reading order, which is depth first through the sections
+func (s *Impl) loadPagesNavigation() {
+	s.pagesNavigation = make(map[string]*PageNavigation)
+
+	root, ok := s.sections[""]
+	if !ok {
+		return
+	}
+
+	readingOrder := make([]string, 0, len(s.pages))
+
+	if root.HasPage {
+		readingOrder = append(readingOrder, root.PageId)
+	}
+
+	var walk func(section *Section)
+
+	walk = func(section *Section) {
+		if section.HasPage || section.Id == "" {
+			children := s.sectionChildren(section)
+
+			for index, pageId := range children {
+				navigation := s.pageNavigationEntry(pageId)
+
+				if index > 0 {
+					navigation.PrevInSection = children[index-1]
+					navigation.HasPrevInSection = true
+				}
+
+				if index < len(children)-1 {
+					navigation.NextInSection = children[index+1]
+					navigation.HasNextInSection = true
+				}
+			}
+		}
+
+		for _, entry := range s.sectionEntries(section) {
+			if entry.section == nil {
+				readingOrder = append(readingOrder, entry.pageId)
+
+				continue
+			}
+
+			if entry.section.HasPage {
+				readingOrder = append(readingOrder, entry.section.PageId)
+			}
+
+			walk(entry.section)
+		}
+	}
+
+	walk(root)
+
+	for index, pageId := range readingOrder {
+		navigation := s.pageNavigationEntry(pageId)
+
+		if index > 0 {
+			navigation.Prev = readingOrder[index-1]
+			navigation.HasPrev = true
+		}
+
+		if index < len(readingOrder)-1 {
+			navigation.Next = readingOrder[index+1]
+			navigation.HasNext = true
+		}
+	}
+}
+
+func (s *Impl) pageNavigationEntry(pageId string) *PageNavigation {
+	navigation, ok := s.pagesNavigation[pageId]
+	if !ok {
+		navigation = &PageNavigation{
+			Prev:             "",
+			HasPrev:          false,
+			Next:             "",
+			HasNext:          false,
+			PrevInSection:    "",
+			HasPrevInSection: false,
+			NextInSection:    "",
+			HasNextInSection: false,
+		}
+
+		s.pagesNavigation[pageId] = navigation
+	}
+
+	return navigation
+}
+
+func (s *Impl) siteSections() []*Section {
+	root, ok := s.sections[""]
+	if !ok {
+		return make([]*Section, 0)
+	}
+
+	return root.Sections
+}
+
+func (s *Impl) pageRelations(page Page) PageRelations {
+	sectionId, isIndex := pageSection(page)
+
+	relations := PageRelations{
+		Section:   sectionId,
+		Parent:    "",
+		HasParent: false,
+		Children:  make([]string, 0),
+		Ancestors: make([]string, 0),
+		PageNavigation: PageNavigation{
+			Prev:             "",
+			HasPrev:          false,
+			Next:             "",
+			HasNext:          false,
+			PrevInSection:    "",
+			HasPrevInSection: false,
+			NextInSection:    "",
+			HasNextInSection: false,
+		},
+	}
+
+	if navigation, ok := s.pagesNavigation[page.Id()]; ok {
+		relations.PageNavigation = *navigation
+	}
+
+	// the index page of a section is a child of the parent section
+	if !isIndex || sectionId != "" {
+		parentId := sectionId
+		if isIndex {
+			parentId = parentSectionId(sectionId)
+		}
+
+		for {
+			if section, ok := s.sections[parentId]; ok && section.HasPage && section.PageId != page.Id() {
+				relations.Ancestors = append(relations.Ancestors, section.PageId)
+			}
+
+			if parentId == "" {
+				break
+			}
+
+			parentId = parentSectionId(parentId)
+		}
+
+		slices.Reverse(relations.Ancestors)
+	}
+
+	if len(relations.Ancestors) > 0 {
+		relations.Parent = relations.Ancestors[len(relations.Ancestors)-1]
+		relations.HasParent = true
+	}
+
+	if section, ok := s.sections[sectionId]; ok && isIndex {
+		relations.Children = s.sectionChildren(section)
+	}
+
+	return relations
+}
+
+func pageRelationsEqual(a PageRelations, b PageRelations) bool {
+	return a.Section == b.Section &&
+		a.Parent == b.Parent &&
+		a.HasParent == b.HasParent &&
+		slices.Equal(a.Children, b.Children) &&
+		slices.Equal(a.Ancestors, b.Ancestors) &&
+		a.PageNavigation == b.PageNavigation
+}
+
+func sectionsEqual(a map[string]*Section, b map[string]*Section) bool {
+	return maps.EqualFunc(a, b, func(aSection *Section, bSection *Section) bool {
+		return aSection.Id == bSection.Id &&
+			aSection.Name == bSection.Name &&
+			aSection.Title == bSection.Title &&
+			aSection.PageId == bSection.PageId &&
+			aSection.HasPage == bSection.HasPage &&
+			aSection.Parent == bSection.Parent &&
+			slices.Equal(aSection.Pages, bSection.Pages) &&
+			slices.EqualFunc(aSection.Sections, bSection.Sections, func(aChild *Section, bChild *Section) bool {
+				return aChild.Id == bChild.Id
+			})
+	})
+}
+
+// sectionChildren are the pages of the section and the index pages of its sections,
+// sections without an index page are flattened
+func (s *Impl) sectionChildren(section *Section) []string {
+	children := make([]string, 0, len(section.Pages)+len(section.Sections))
+
+	for _, entry := range s.sectionEntries(section) {
+		switch {
+		case entry.section == nil:
+			children = append(children, entry.pageId)
+
+		case entry.section.HasPage:
+			children = append(children, entry.section.PageId)
+
+		default:
+			children = append(children, s.sectionChildren(entry.section)...)
+		}
+	}
+
+	return children
+}
+
+// sectionEntries are the pages and the sections of the section in the pages order
+func (s *Impl) sectionEntries(section *Section) []sectionEntry {
+	entries := make([]sectionEntry, 0, len(section.Pages)+len(section.Sections))
+
+	for _, pageId := range section.Pages {
+		entries = append(entries, sectionEntry{
+			pageId:  pageId,
+			section: nil,
+		})
+	}
+
+	for _, childSection := range section.Sections {
+		entries = append(entries, sectionEntry{
+			pageId:  childSection.PageId,
+			section: childSection,
+		})
+	}
+
+	slices.SortStableFunc(entries, func(a, b sectionEntry) int {
+		return s.compareSectionEntries(a, b)
+	})
+
+	return entries
+}
+
+func (s *Impl) compareSections(a *Section, b *Section) int {
+	return s.compareSectionEntries(
+		sectionEntry{pageId: a.PageId, section: a},
+		sectionEntry{pageId: b.PageId, section: b},
+	)
+}
+
+// compareSectionEntries orders sections by their index pages, sections without one go last
+func (s *Impl) compareSectionEntries(a sectionEntry, b sectionEntry) int {
+	pageA, okA := s.sectionEntryPage(a)
+	pageB, okB := s.sectionEntryPage(b)
+
+	switch {
+	case okA && okB:
+		return comparePages(pageA, pageB)
+
+	case okA:
+		return -1
+
+	case okB:
+		return 1
+
+	default:
+		return strings.Compare(a.section.Id, b.section.Id)
+	}
+}
+
+func (s *Impl) sectionEntryPage(entry sectionEntry) (Page, bool) {
+	if entry.section != nil && !entry.section.HasPage {
+		return nil, false
+	}
+
+	page, ok := s.pages[entry.pageId]
+
+	return page, ok
+}
+
+func pageSection(page Page) (string, bool) {
+	pageName := filepath.ToSlash(page.Name())
+
+	return parentSectionId(pageName), path.Base(pageName) == "index"
+}
+
+func parentSectionId(sectionId string) string {
+	parentId := path.Dir(sectionId)
+	if parentId == "." || parentId == "/" {
+		return ""
+	}
+
+	return parentId
+}
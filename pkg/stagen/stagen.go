@@ -25,16 +25,25 @@ var (
 	ErrLoadAggDict               = errors.New("agg dict load")
 	ErrLoadGenerator             = errors.New("generator load")
 	ErrLoadPage                  = errors.New("page load")
+	ErrLoadAlias                 = errors.New("alias load")
 	ErrStorageIsNotALocalStorage = errors.New("storage is not a local storage")
+	ErrBuildDirParentNotWritable = errors.New("build directory parent is not writable")
+	ErrBuildDirNotADirectory     = errors.New("build directory is not a directory")
 )
 
 var (
-	databaseFilenameRegexp   = regexp.MustCompile(`^(.*)\.(yml|yaml)$`)
-	pageIgnoreFilenameRegexp = regexp.MustCompile(`(^\.|^(.*)\.(yml|yaml)$)`)
-	templateExtensionRegexp  = regexp.MustCompile(`(\.tmpl)`)
-	markdownExtensionRegexp  = regexp.MustCompile(`(\.md)`)
-	htmlExtensionRegexp      = regexp.MustCompile(`(\.html|\.htm)`)
-	configFilenames          = []string{
+	databaseFilenameRegexp     = regexp.MustCompile(`^(.*)\.(yml|yaml)$`)
+	pageIgnoreFilenameRegexp   = regexp.MustCompile(`(^\.|^(.*)\.(yml|yaml)$)`)
+	templateExtensionRegexp    = regexp.MustCompile(`(\.tmpl)`)
+	markdownExtensionRegexp    = regexp.MustCompile(`(\.md)`)
+	htmlExtensionRegexp        = regexp.MustCompile(`(\.html|\.htm)`)
+	templateDataKeys           = []string{"Pages", "Sections", "AggDicts", "AggDictsData", "Databases"}
+	frontMatterErrorRegexp     = regexp.MustCompile(`(?s)line (\d+): (.*)$`)
+	permalinkPlaceholderRegexp = regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_]*)`)
+	summaryMarkerRegexp        = regexp.MustCompile(`<!--\s*more\s*-->`)
+	firstParagraphRegexp       = regexp.MustCompile(`(?is)<p[\s>].*?</p>`)
+	htmlTagRegexp              = regexp.MustCompile(`(?s)<!--.*?-->|<[^>]*>`)
+	configFilenames            = []string{
 		"config.yml",
 		"config.yaml",
 	}
@@ -43,31 +52,65 @@ var (
 type Stagen interface {
 	NewProject(ctx context.Context, name string, withGit bool) error
 	Build(ctx context.Context) error
+	Check(ctx context.Context) ([]CheckProblem, error)
 	Watch(ctx context.Context) error
+	Rebuild(ctx context.Context, changedFiles []string) error
 	Web(ctx context.Context) error
 }
 
+type Option func(s *Impl)
+
+// WithBuildTime pins System.BuildTime instead of taking it from the clock, the clock keeps serving Now
+func WithBuildTime(buildTime time.Time) Option {
+	return func(s *Impl) {
+		s.fixedBuildTime = buildTime
+	}
+}
+
 type Impl struct {
-	log          logger.Loggable
-	config       Config
-	siteConfig   SiteConfig
-	clock        clock.Clock
-	git          git.Git
-	storage      storage.Storage
-	workDir      string
-	realWorkDir  string
-	buildTime    time.Time
-	initialized  bool
-	extensions   map[string]Extension
-	databases    map[string]Database
-	aggDicts     map[string]SiteAggDictConfig
-	aggDictsData map[string]map[string]map[string][]Page
-	generators   map[string]Generator
-	pages        map[string]Page
-	themes       map[string]Theme
-	createdDirs  map[string]struct{}
-	initMutex    sync.Mutex
-	rebuildMutex sync.Mutex
+	log                   logger.Loggable
+	config                Config
+	siteConfig            SiteConfig
+	clock                 clock.Clock
+	git                   git.Git
+	storage               storage.Storage
+	workDir               string
+	realWorkDir           string
+	configFile            string
+	buildTime             time.Time
+	fixedBuildTime        time.Time
+	initialized           bool
+	extensions            map[string]Extension
+	databases             map[string]Database
+	aggDicts              map[string]SiteAggDictConfig
+	aggDictsData          map[string]map[string]map[string][]Page
+	generators            map[string]Generator
+	generatedPages        map[string]string
+	pages                 map[string]Page
+	aliases               map[string]*PageAlias
+	passthroughFiles      map[string]string
+	pagesTexts            map[string]*PageText
+	pagesContents         map[string]*PageContent
+	pagesData             map[string]any
+	sections              map[string]*Section
+	pagesNavigation       map[string]*PageNavigation
+	paginators            map[string]*Paginator
+	languages             []*Language
+	pagesTranslations     map[string][]*PageTranslation
+	i18n                  map[string]map[string]string
+	pagesGitInfo          map[string]*PageGitInfo
+	themes                map[string]Theme
+	createdDirs           map[string]struct{}
+	dependencies          *DependencyGraph
+	buildManifest         *BuildManifest
+	previousBuildManifest *BuildManifest
+	stagingDir            string
+	report                *BuildReport
+	check                 *CheckResult
+	initMutex             sync.Mutex
+	rebuildMutex          sync.Mutex
+	dirsMutex             sync.Mutex
+	pagesDataMutex        sync.Mutex
 }
 
 func New(
@@ -77,29 +120,60 @@ func New(
 	gitTool git.Git,
 	storage storage.Storage,
 	realWorkDir string,
+	configFile string,
+	opts ...Option,
 ) *Impl {
-	return &Impl{
-		log:          logger.NewLoggableImplWithService("stagen"),
-		config:       cfg,
-		siteConfig:   siteConfig,
-		clock:        clock,
-		git:          gitTool,
-		storage:      storage,
-		workDir:      "",
-		realWorkDir:  realWorkDir,
-		buildTime:    clock.Now(),
-		initialized:  false,
-		extensions:   make(map[string]Extension),
-		databases:    make(map[string]Database),
-		aggDicts:     make(map[string]SiteAggDictConfig),
-		aggDictsData: make(map[string]map[string]map[string][]Page),
-		generators:   make(map[string]Generator),
-		pages:        make(map[string]Page),
-		themes:       make(map[string]Theme),
-		createdDirs:  make(map[string]struct{}),
-		initMutex:    sync.Mutex{},
-		rebuildMutex: sync.Mutex{},
+	stagenTool := &Impl{
+		log:                   logger.NewLoggableImplWithService("stagen"),
+		config:                cfg,
+		siteConfig:            siteConfig,
+		clock:                 clock,
+		git:                   gitTool,
+		storage:               storage,
+		workDir:               "",
+		realWorkDir:           realWorkDir,
+		configFile:            configFile,
+		buildTime:             clock.Now(),
+		fixedBuildTime:        time.Time{},
+		initialized:           false,
+		extensions:            make(map[string]Extension),
+		databases:             make(map[string]Database),
+		aggDicts:              make(map[string]SiteAggDictConfig),
+		aggDictsData:          make(map[string]map[string]map[string][]Page),
+		generators:            make(map[string]Generator),
+		generatedPages:        make(map[string]string),
+		pages:                 make(map[string]Page),
+		aliases:               make(map[string]*PageAlias),
+		passthroughFiles:      make(map[string]string),
+		pagesTexts:            make(map[string]*PageText),
+		pagesContents:         make(map[string]*PageContent),
+		pagesData:             nil,
+		sections:              make(map[string]*Section),
+		pagesNavigation:       make(map[string]*PageNavigation),
+		paginators:            make(map[string]*Paginator),
+		languages:             make([]*Language, 0),
+		pagesTranslations:     make(map[string][]*PageTranslation),
+		i18n:                  make(map[string]map[string]string),
+		pagesGitInfo:          make(map[string]*PageGitInfo),
+		themes:                make(map[string]Theme),
+		createdDirs:           make(map[string]struct{}),
+		dependencies:          NewDependencyGraph(),
+		buildManifest:         NewBuildManifest(""),
+		previousBuildManifest: NewBuildManifest(""),
+		stagingDir:            "",
+		report:                NewBuildReport(),
+		check:                 nil,
+		initMutex:             sync.Mutex{},
+		rebuildMutex:          sync.Mutex{},
+		dirsMutex:             sync.Mutex{},
+		pagesDataMutex:        sync.Mutex{},
 	}
+
+	for _, opt := range opts {
+		opt(stagenTool)
+	}
+
+	return stagenTool
 }
 
 func (s *Impl) templatesDir() string {
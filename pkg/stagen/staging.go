@@ -0,0 +1,360 @@
+package stagen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pixality-inc/golang-core/storage"
+	"github.com/pixality-inc/golang-core/util"
+
+	"github.com/stagens/stagen/pkg/filetree"
+)
+
+const (
+	// buildLinkSuffix is the sibling of the build directory the new build link is made at before it replaces it
+	buildLinkSuffix = "link"
+	// buildOldSuffix is the sibling a build directory of an older version is moved to for the link
+	buildOldSuffix = "old"
+)
+
+// buildVersions are the suffixes of the siblings the build directory links to in turn,
+// the next build is staged in the one it does not link to
+var buildVersions = []string{"a", "b"}
+
+func (s *Impl) outputDir() string {
+	if s.stagingDir != "" {
+		return s.stagingDir
+	}
+
+	return s.buildDir()
+}
+
+func (s *Impl) siblingBuildDir(suffix string) string {
+	buildDir := s.buildDir()
+
+	return filepath.Join(filepath.Dir(buildDir), "."+filepath.Base(buildDir)+"."+suffix)
+}
+
+func (s *Impl) localPath(ctx context.Context, filename string) (string, error) {
+	// @todo!!!!
+	localStorage, ok := s.storage.(storage.LocalStorage)
+	if !ok {
+		return "", ErrStorageIsNotALocalStorage
+	}
+
+	localFilename, err := localStorage.LocalPath(ctx, filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to get local file path: %w", err)
+	}
+
+	return localFilename, nil
+}
+
+func (s *Impl) createStagingDir(ctx context.Context) (string, error) {
+	stagingDir, err := s.nextBuildVersionDir(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if err = s.removeStagingDir(ctx, stagingDir); err != nil {
+		return "", err
+	}
+
+	if err = s.storage.MkDir(ctx, stagingDir); err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%w: the build is staged in '%s': %w", ErrBuildDirParentNotWritable, stagingDir, err)
+		}
+
+		return "", fmt.Errorf("failed to create staging directory '%s': %w", stagingDir, err)
+	}
+
+	return stagingDir, nil
+}
+
+// nextBuildVersionDir is the sibling of the build directory it does not link to
+func (s *Impl) nextBuildVersionDir(ctx context.Context) (string, error) {
+	buildLocalPath, err := s.localPath(ctx, s.buildDir())
+	if err != nil {
+		return "", err
+	}
+
+	// a build directory that is not a link yet links to none of them
+	target, err := os.Readlink(buildLocalPath)
+	if err != nil {
+		target = ""
+	}
+
+	for _, buildVersion := range buildVersions {
+		versionDir := s.siblingBuildDir(buildVersion)
+
+		if filepath.Base(versionDir) != filepath.Base(target) {
+			return versionDir, nil
+		}
+	}
+
+	return s.siblingBuildDir(buildVersions[0]), nil
+}
+
+// inStaging runs the build into a fresh staging directory and swaps it in only when the build succeeds
+func (s *Impl) inStaging(ctx context.Context, build func() error) error {
+	stagingDir, err := s.createStagingDir(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.stagingDir = stagingDir
+
+	defer func() {
+		s.stagingDir = ""
+	}()
+
+	if err = build(); err != nil {
+		if rErr := s.removeStagingDir(ctx, stagingDir); rErr != nil {
+			s.log.GetLogger(ctx).WithError(rErr).Errorf("failed to remove staging directory")
+		}
+
+		return err
+	}
+
+	if err = s.swapBuildDir(ctx, stagingDir); err != nil {
+		return fmt.Errorf("failed to swap build directory: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Impl) removeStagingDir(ctx context.Context, stagingDir string) error {
+	if exists, err := s.storage.FileExists(ctx, stagingDir); err != nil {
+		return fmt.Errorf("failed to check if file %s exists: %w", stagingDir, err)
+	} else if !exists {
+		return nil
+	}
+
+	if err := s.storage.DeleteDir(ctx, stagingDir); err != nil {
+		return fmt.Errorf("failed to remove staging directory '%s': %w", stagingDir, err)
+	}
+
+	return nil
+}
+
+// linkBuildFile reuses a file of the live build in the staging directory, so unchanged files keep their mtime
+func (s *Impl) linkBuildFile(ctx context.Context, filename string) error {
+	sourceLocalPath, err := s.localPath(ctx, filepath.Join(s.buildDir(), filename))
+	if err != nil {
+		return err
+	}
+
+	destFilename := filepath.Join(s.outputDir(), filename)
+
+	if err = s.createBuildDir(ctx, filepath.Dir(destFilename)); err != nil {
+		return err
+	}
+
+	destLocalPath, err := s.localPath(ctx, destFilename)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Link(sourceLocalPath, destLocalPath); err == nil {
+		return nil
+	}
+
+	return copyBuildFile(sourceLocalPath, destLocalPath)
+}
+
+// copyBuildFile copies a file keeping its mode and mtime, so tools comparing them don't see it as changed
+func copyBuildFile(sourceLocalPath string, destLocalPath string) error {
+	info, err := os.Stat(sourceLocalPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat file '%s': %w", sourceLocalPath, err)
+	}
+
+	if err = util.CopyFile(sourceLocalPath, destLocalPath); err != nil {
+		return fmt.Errorf("failed to copy file '%s' to '%s': %w", sourceLocalPath, destLocalPath, err)
+	}
+
+	if err = os.Chmod(destLocalPath, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to set mode of file '%s': %w", destLocalPath, err)
+	}
+
+	if err = os.Chtimes(destLocalPath, info.ModTime(), info.ModTime()); err != nil {
+		return fmt.Errorf("failed to set times of file '%s': %w", destLocalPath, err)
+	}
+
+	return nil
+}
+
+// linkUnchangedBuildFiles fills the staging directory of an incremental rebuild with the files it did not write
+func (s *Impl) linkUnchangedBuildFiles(ctx context.Context) error {
+	for _, filename := range s.buildManifest.Filenames() {
+		stagingFilename := filepath.Join(s.outputDir(), filename)
+
+		if exists, err := s.storage.FileExists(ctx, stagingFilename); err != nil {
+			return fmt.Errorf("failed to check if file %s exists: %w", stagingFilename, err)
+		} else if exists {
+			continue
+		}
+
+		buildFilename := filepath.Join(s.buildDir(), filename)
+
+		// a file removed from the live build by hand is rendered again by the next full build
+		if exists, err := s.storage.FileExists(ctx, buildFilename); err != nil {
+			return fmt.Errorf("failed to check if file %s exists: %w", buildFilename, err)
+		} else if !exists {
+			s.buildManifest.Delete(filename)
+
+			continue
+		}
+
+		if err := s.linkBuildFile(ctx, filename); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Impl) carryOverForeignFiles(ctx context.Context) error {
+	buildDir := s.buildDir()
+
+	if exists, err := s.storage.FileExists(ctx, buildDir); err != nil {
+		return fmt.Errorf("failed to check if file %s exists: %w", buildDir, err)
+	} else if !exists {
+		return nil
+	}
+
+	tree, err := filetree.Tree(ctx, s.storage, buildDir, filetree.NoMaxLevel)
+	if err != nil {
+		return fmt.Errorf("failed to create tree for dir '%s': %w", buildDir, err)
+	}
+
+	return filetree.Visit(ctx, tree, func(entry filetree.Entry) error {
+		if entry.IsDir() {
+			return nil
+		}
+
+		filename, _ := strings.CutPrefix(filepath.Join(entry.Path(), entry.Name()), buildDir+"/")
+
+		if _, ok := s.previousBuildManifest.Get(filename); ok {
+			return nil
+		}
+
+		if _, ok := s.buildManifest.Get(filename); ok {
+			return nil
+		}
+
+		s.log.GetLogger(ctx).Debugf("Keeping foreign file '%s'", filename)
+
+		return s.linkBuildFile(ctx, filename)
+	})
+}
+
+// swapBuildDir points the build directory to the staging one, the build directory is a link to it
+// which replaces the previous link by a rename, so the build directory always holds a whole build.
+// A build directory of an older version is a directory, it is moved aside once before the first link.
+func (s *Impl) swapBuildDir(ctx context.Context, stagingDir string) error {
+	buildDir := s.buildDir()
+
+	buildLocalPath, err := s.localPath(ctx, buildDir)
+	if err != nil {
+		return err
+	}
+
+	stagingLocalPath, err := s.localPath(ctx, stagingDir)
+	if err != nil {
+		return err
+	}
+
+	linkLocalPath, err := s.localPath(ctx, s.siblingBuildDir(buildLinkSuffix))
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(linkLocalPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove build link '%s': %w", linkLocalPath, err)
+	}
+
+	// the link is relative, so the project can be moved with its build
+	if err = os.Symlink(filepath.Base(stagingLocalPath), linkLocalPath); err != nil {
+		return fmt.Errorf("failed to create build link '%s': %w", linkLocalPath, err)
+	}
+
+	previousLocalPath, movedAside, err := s.previousBuildDir(ctx, buildLocalPath)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Rename(linkLocalPath, buildLocalPath); err != nil {
+		if movedAside {
+			if rErr := os.Rename(previousLocalPath, buildLocalPath); rErr != nil {
+				s.log.GetLogger(ctx).WithError(rErr).Errorf("failed to restore build directory")
+			}
+		}
+
+		return fmt.Errorf("failed to replace build directory with build link: %w", err)
+	}
+
+	s.dirsMutex.Lock()
+	s.createdDirs = make(map[string]struct{})
+	s.dirsMutex.Unlock()
+
+	if previousLocalPath == "" || previousLocalPath == stagingLocalPath {
+		return nil
+	}
+
+	if err = os.RemoveAll(previousLocalPath); err != nil {
+		return fmt.Errorf("failed to remove previous build directory '%s': %w", previousLocalPath, err)
+	}
+
+	return nil
+}
+
+// previousBuildDir is the directory of the live build, a build directory of an older version is moved aside for the link
+func (s *Impl) previousBuildDir(ctx context.Context, buildLocalPath string) (string, bool, error) {
+	info, err := os.Lstat(buildLocalPath)
+
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return "", false, nil
+
+	case err != nil:
+		return "", false, fmt.Errorf("failed to stat build directory '%s': %w", buildLocalPath, err)
+
+	case info.Mode()&fs.ModeSymlink != 0:
+		target, err := os.Readlink(buildLocalPath)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read build link '%s': %w", buildLocalPath, err)
+		}
+
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(buildLocalPath), target)
+		}
+
+		return target, false, nil
+
+	case !info.IsDir():
+		return "", false, fmt.Errorf("%w: %s", ErrBuildDirNotADirectory, buildLocalPath)
+	}
+
+	oldDir := s.siblingBuildDir(buildOldSuffix)
+
+	if err = s.removeStagingDir(ctx, oldDir); err != nil {
+		return "", false, err
+	}
+
+	oldLocalPath, err := s.localPath(ctx, oldDir)
+	if err != nil {
+		return "", false, err
+	}
+
+	if err = os.Rename(buildLocalPath, oldLocalPath); err != nil {
+		return "", false, fmt.Errorf("failed to move build directory aside: %w", err)
+	}
+
+	return oldLocalPath, true, nil
+}
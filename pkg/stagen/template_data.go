@@ -0,0 +1,498 @@
+package stagen
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template/parse"
+)
+
+// templateDataMaxDepth bounds the nested template calls, deeper calls are taken as using all data
+const templateDataMaxDepth = 32
+
+var templateTextDataFields = []string{"Pages", "Sections", "Paginator", "Summary", "Plain", "WordCount", "ReadingTime", "Toc"}
+
+// templateValue is what a template value may hold: the template data, the entries of a dict made in the template,
+// or both when it depends on the branch taken, nil is a value that can't reach the template data
+type templateValue struct {
+	data   bool
+	fields map[string]*templateValue
+}
+
+var templateDataValue = &templateValue{data: true, fields: nil}
+
+func mergeTemplateValues(values ...*templateValue) *templateValue {
+	var result *templateValue
+
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+
+		if result == nil {
+			result = value
+
+			continue
+		}
+
+		fields := make(map[string]*templateValue, len(result.fields)+len(value.fields))
+
+		for _, name := range slices.Concat(slices.Collect(maps.Keys(result.fields)), slices.Collect(maps.Keys(value.fields))) {
+			fields[name] = mergeTemplateValues(result.fields[name], value.fields[name])
+		}
+
+		result = &templateValue{
+			data:   result.data || value.data,
+			fields: fields,
+		}
+	}
+
+	return result
+}
+
+// key tells the values apart for the templates already walked with them
+func (v *templateValue) key() string {
+	if v == nil {
+		return "-"
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString(strconv.FormatBool(v.data))
+
+	for _, name := range slices.Sorted(maps.Keys(v.fields)) {
+		builder.WriteString(" " + strconv.Quote(name) + ":(" + v.fields[name].key() + ")")
+	}
+
+	return builder.String()
+}
+
+type templateScope struct {
+	parent    *templateScope
+	variables map[string]*templateValue
+}
+
+func newTemplateScope(parent *templateScope) *templateScope {
+	return &templateScope{
+		parent:    parent,
+		variables: make(map[string]*templateValue),
+	}
+}
+
+func (s *templateScope) lookup(name string) *templateValue {
+	for scope := s; scope != nil; scope = scope.parent {
+		if value, ok := scope.variables[name]; ok {
+			return value
+		}
+	}
+
+	return nil
+}
+
+// assign keeps the previous value too, the assignment may be in a branch that is not taken
+func (s *templateScope) assign(name string, value *templateValue) {
+	for scope := s; scope != nil; scope = scope.parent {
+		if previous, ok := scope.variables[name]; ok {
+			scope.variables[name] = mergeTemplateValues(previous, value)
+
+			return
+		}
+	}
+
+	s.variables[name] = value
+}
+
+// templateDataUsage is what the executed templates read from the template data,
+// the texts of the pages are not ready for the page content
+type templateDataUsage struct {
+	dataKeys    []string
+	hasTextData bool
+}
+
+// templateDataWalker follows the template data through the parse trees,
+// what it can't follow is taken as reading all the data
+type templateDataWalker struct {
+	trees       map[string]*parse.Tree
+	dataKeys    map[string]struct{}
+	hasTextData bool
+	allData     bool
+	walked      map[string]struct{}
+	depth       int
+}
+
+// usedTemplateData walks the templates executed with the template data, by their names, through the templates they call
+func usedTemplateData(trees map[string]*parse.Tree, names []string) templateDataUsage {
+	walker := &templateDataWalker{
+		trees:       trees,
+		dataKeys:    make(map[string]struct{}),
+		hasTextData: false,
+		allData:     false,
+		walked:      make(map[string]struct{}),
+		depth:       0,
+	}
+
+	for _, name := range names {
+		walker.template(name, templateDataValue)
+	}
+
+	if walker.allData {
+		return templateDataUsage{
+			dataKeys:    slices.Clone(templateDataKeys),
+			hasTextData: true,
+		}
+	}
+
+	return templateDataUsage{
+		dataKeys:    slices.Sorted(maps.Keys(walker.dataKeys)),
+		hasTextData: walker.hasTextData,
+	}
+}
+
+// template walks the named template with the dot value, a template that is not parsed was not executed,
+// it is parsed and walked when a change of the data it depends on renders the page again
+func (w *templateDataWalker) template(name string, dot *templateValue) {
+	tree, ok := w.trees[name]
+	if !ok || tree.Root == nil {
+		return
+	}
+
+	walkedKey := strconv.Quote(name) + " " + dot.key()
+
+	if _, ok = w.walked[walkedKey]; ok {
+		return
+	}
+
+	w.walked[walkedKey] = struct{}{}
+
+	if w.depth >= templateDataMaxDepth {
+		w.allData = true
+
+		return
+	}
+
+	w.depth++
+	defer func() { w.depth-- }()
+
+	scope := newTemplateScope(nil)
+	scope.variables["$"] = dot
+
+	w.list(scope, dot, tree.Root)
+}
+
+func (w *templateDataWalker) list(scope *templateScope, dot *templateValue, list *parse.ListNode) {
+	if list == nil {
+		return
+	}
+
+	for _, node := range list.Nodes {
+		w.node(scope, dot, node)
+	}
+}
+
+func (w *templateDataWalker) node(scope *templateScope, dot *templateValue, node parse.Node) {
+	switch node := node.(type) {
+	case *parse.ActionNode:
+		w.pipe(scope, dot, node.Pipe)
+
+	case *parse.IfNode:
+		branchScope := newTemplateScope(scope)
+
+		w.pipe(branchScope, dot, node.Pipe)
+		w.list(branchScope, dot, node.List)
+		w.list(branchScope, dot, node.ElseList)
+
+	case *parse.WithNode:
+		branchScope := newTemplateScope(scope)
+
+		w.list(branchScope, w.pipe(branchScope, dot, node.Pipe), node.List)
+		w.list(branchScope, dot, node.ElseList)
+
+	case *parse.RangeNode:
+		branchScope := newTemplateScope(scope)
+
+		element := w.rangeElement(w.pipeValue(branchScope, dot, node.Pipe))
+
+		// the first of two variables is the key, a single one is the element
+		for index, variable := range node.Pipe.Decl {
+			value := element
+			if index == 0 && len(node.Pipe.Decl) > 1 {
+				value = nil
+			}
+
+			w.declare(branchScope, node.Pipe.IsAssign, variable, value)
+		}
+
+		// the second pass sees the values assigned by the previous iteration
+		w.list(branchScope, element, node.List)
+		w.list(branchScope, element, node.List)
+		w.list(branchScope, dot, node.ElseList)
+
+	case *parse.TemplateNode:
+		w.template(node.Name, w.pipe(scope, dot, node.Pipe))
+
+	case *parse.ListNode:
+		w.list(scope, dot, node)
+	}
+}
+
+// rangeElement is what the range dot may be, ranging over the template data goes over all of it
+func (w *templateDataWalker) rangeElement(value *templateValue) *templateValue {
+	if value == nil {
+		return nil
+	}
+
+	if value.data {
+		w.allData = true
+	}
+
+	return mergeTemplateValues(slices.Collect(maps.Values(value.fields))...)
+}
+
+func (w *templateDataWalker) declare(scope *templateScope, isAssign bool, variable *parse.VariableNode, value *templateValue) {
+	if isAssign {
+		scope.assign(variable.Ident[0], value)
+	} else {
+		scope.variables[variable.Ident[0]] = value
+	}
+}
+
+func (w *templateDataWalker) pipe(scope *templateScope, dot *templateValue, pipe *parse.PipeNode) *templateValue {
+	value := w.pipeValue(scope, dot, pipe)
+
+	if pipe != nil {
+		for _, variable := range pipe.Decl {
+			w.declare(scope, pipe.IsAssign, variable, value)
+		}
+	}
+
+	return value
+}
+
+func (w *templateDataWalker) pipeValue(scope *templateScope, dot *templateValue, pipe *parse.PipeNode) *templateValue {
+	if pipe == nil {
+		return nil
+	}
+
+	var value *templateValue
+
+	for index, command := range pipe.Cmds {
+		value = w.command(scope, dot, command, value, index > 0)
+	}
+
+	return value
+}
+
+// command is the value of the command, the value of the previous command of the pipeline is its last argument
+func (w *templateDataWalker) command(
+	scope *templateScope,
+	dot *templateValue,
+	command *parse.CommandNode,
+	piped *templateValue,
+	isPiped bool,
+) *templateValue {
+	if len(command.Args) == 0 {
+		return nil
+	}
+
+	argsNodes := command.Args[1:]
+
+	args := make([]*templateValue, 0, len(command.Args))
+
+	for _, argNode := range argsNodes {
+		args = append(args, w.arg(scope, dot, argNode))
+	}
+
+	if isPiped {
+		args = append(args, piped)
+	}
+
+	identifier, ok := command.Args[0].(*parse.IdentifierNode)
+	if !ok {
+		// the arguments of a method could be read in any way
+		w.use(args...)
+
+		return w.arg(scope, dot, command.Args[0])
+	}
+
+	return w.call(identifier.Ident, argsNodes, args)
+}
+
+func (w *templateDataWalker) arg(scope *templateScope, dot *templateValue, node parse.Node) *templateValue {
+	switch node := node.(type) {
+	case *parse.DotNode:
+		return dot
+
+	case *parse.FieldNode:
+		return w.fields(dot, node.Ident)
+
+	case *parse.VariableNode:
+		return w.fields(scope.lookup(node.Ident[0]), node.Ident[1:])
+
+	case *parse.ChainNode:
+		return w.fields(w.arg(scope, dot, node.Node), node.Field)
+
+	case *parse.PipeNode:
+		return w.pipe(scope, dot, node)
+
+	case *parse.IdentifierNode:
+		return w.call(node.Ident, nil, nil)
+
+	default:
+		return nil
+	}
+}
+
+func (w *templateDataWalker) fields(value *templateValue, names []string) *templateValue {
+	for _, name := range names {
+		value = w.field(value, name)
+	}
+
+	return value
+}
+
+func (w *templateDataWalker) field(value *templateValue, name string) *templateValue {
+	if slices.Contains(templateTextDataFields, name) {
+		w.hasTextData = true
+	}
+
+	if value == nil {
+		return nil
+	}
+
+	if value.data && slices.Contains(templateDataKeys, name) {
+		w.dataKeys[name] = struct{}{}
+	}
+
+	return value.fields[name]
+}
+
+// call is the value of the function call, the arguments nodes are nil for the piped argument
+func (w *templateDataWalker) call(function string, argsNodes []parse.Node, args []*templateValue) *templateValue {
+	switch function {
+	case "dict":
+		return w.dict(argsNodes, args)
+
+	case "index":
+		return w.index(argsNodes, args)
+
+	case "default", "and", "or":
+		return mergeTemplateValues(args...)
+
+	case "page_content":
+		w.template("page_content", templateDataValue)
+
+	case "render":
+		w.calledTemplate(argsNodes, 0, "", templateDataValue)
+
+	case "include":
+		w.calledTemplate(argsNodes, 0, "", templateDataWith(args, 1))
+
+	case "macro":
+		w.calledTemplate(argsNodes, 0, "macro:", templateDataWith(args, 1))
+
+	case "macro_render":
+		w.calledTemplate(argsNodes, 1, "", templateDataValue)
+		w.calledTemplate(argsNodes, 0, "macro:", templateDataWith(args, 2))
+
+	case "toc":
+		w.hasTextData = true
+
+	case "includes", "extends", "import":
+		// the includes are walked by their names when they are rendered, the others only parse templates
+
+	default:
+		w.use(args...)
+	}
+
+	return nil
+}
+
+// calledTemplate walks the template named by the argument, a computed name could be any template
+func (w *templateDataWalker) calledTemplate(argsNodes []parse.Node, index int, prefix string, dot *templateValue) {
+	name, ok := stringArg(argsNodes, index)
+	if !ok {
+		w.allData = true
+
+		return
+	}
+
+	w.template(prefix+name, dot)
+}
+
+// templateDataWith is the template data with the entries of the dict argument, like the engine merges them
+func templateDataWith(args []*templateValue, index int) *templateValue {
+	if index >= len(args) || args[index] == nil {
+		return templateDataValue
+	}
+
+	return mergeTemplateValues(templateDataValue, &templateValue{data: false, fields: args[index].fields})
+}
+
+func (w *templateDataWalker) dict(argsNodes []parse.Node, args []*templateValue) *templateValue {
+	fields := make(map[string]*templateValue)
+
+	for index := 0; index+1 < len(args); index += 2 {
+		if args[index+1] == nil {
+			continue
+		}
+
+		name, ok := stringArg(argsNodes, index)
+		if !ok {
+			w.use(args[index+1])
+
+			continue
+		}
+
+		fields[name] = args[index+1]
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return &templateValue{data: false, fields: fields}
+}
+
+func (w *templateDataWalker) index(argsNodes []parse.Node, args []*templateValue) *templateValue {
+	if len(args) == 0 {
+		return nil
+	}
+
+	value := args[0]
+
+	for index := 1; index < len(args); index++ {
+		name, ok := stringArg(argsNodes, index)
+		if !ok {
+			w.use(value)
+
+			value = nil
+
+			continue
+		}
+
+		value = w.field(value, name)
+	}
+
+	return value
+}
+
+// use marks the values that are read in an unknown way, the template data in them is read in full
+func (w *templateDataWalker) use(values ...*templateValue) {
+	if slices.ContainsFunc(values, func(value *templateValue) bool { return value != nil }) {
+		w.allData = true
+	}
+}
+
+func stringArg(argsNodes []parse.Node, index int) (string, bool) {
+	if index >= len(argsNodes) {
+		return "", false
+	}
+
+	stringNode, ok := argsNodes[index].(*parse.StringNode)
+	if !ok {
+		return "", false
+	}
+
+	return stringNode.Text, true
+}
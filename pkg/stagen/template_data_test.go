@@ -0,0 +1,171 @@
+package stagen
+
+import (
+	"testing"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parseTestTemplates(t *testing.T, templates map[string]string) map[string]*parse.Tree {
+	t.Helper()
+
+	functions := template.FuncMap{}
+
+	for _, name := range []string{"dict", "default", "page_content", "include", "render", "macro", "macro_render", "toc", "includes", "T"} {
+		functions[name] = func(...any) string { return "" }
+	}
+
+	tmpl, err := template.New("root").Funcs(functions).Parse(templates["root"])
+	require.NoError(t, err)
+
+	for name, content := range templates {
+		if name != "root" {
+			_, err = tmpl.New(name).Parse(content)
+			require.NoError(t, err)
+		}
+	}
+
+	trees := make(map[string]*parse.Tree)
+
+	for _, associated := range tmpl.Templates() {
+		trees[associated.Name()] = associated.Tree
+	}
+
+	return trees
+}
+
+func TestUsedTemplateData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		templates   map[string]string
+		dataKeys    []string
+		hasTextData bool
+	}{
+		{
+			name:        "fields",
+			templates:   map[string]string{"root": `{{ .Page.Title }} {{ range .Databases.colors.Data }}{{ .id }}{{ end }}`},
+			dataKeys:    []string{"Databases"},
+			hasTextData: false,
+		},
+		{
+			name:        "no data",
+			templates:   map[string]string{"root": `{{ .Page.Title }} {{ printf "%s" .Page.Uri }}`},
+			dataKeys:    nil,
+			hasTextData: false,
+		},
+		{
+			name:        "variable alias",
+			templates:   map[string]string{"root": `{{ $site := . }}{{ with .Page }}{{ $site.Sections }}{{ $.AggDicts }}{{ end }}`},
+			dataKeys:    []string{"AggDicts", "Sections"},
+			hasTextData: true,
+		},
+		{
+			name:        "assigned variable",
+			templates:   map[string]string{"root": `{{ $value := .Page }}{{ if .Page.Title }}{{ $value = . }}{{ end }}{{ $value.Databases }}`},
+			dataKeys:    []string{"Databases"},
+			hasTextData: false,
+		},
+		{
+			name:        "with",
+			templates:   map[string]string{"root": `{{ with . }}{{ .AggDictsData }}{{ end }}{{ with .Page }}{{ .Databases }}{{ end }}`},
+			dataKeys:    []string{"AggDictsData"},
+			hasTextData: false,
+		},
+		{
+			name: "template with dict",
+			templates: map[string]string{
+				"root": `{{ template "list" (dict "site" . "items" .Page.Items) }}`,
+				"list": `{{ range .items }}{{ .Databases }}{{ end }}{{ .site.Pages }}`,
+			},
+			dataKeys:    []string{"Pages"},
+			hasTextData: true,
+		},
+		{
+			name: "template with field",
+			templates: map[string]string{
+				"root": `{{ template "list" .Pages }}`,
+				"list": `{{ range . }}{{ .Title }}{{ end }}`,
+			},
+			dataKeys:    []string{"Pages"},
+			hasTextData: true,
+		},
+		{
+			name: "include with dict",
+			templates: map[string]string{
+				"root":    `{{ include "partial" (dict "site" .) }}`,
+				"partial": `{{ .Sections }}{{ .site.Databases }}`,
+			},
+			dataKeys:    []string{"Databases", "Sections"},
+			hasTextData: true,
+		},
+		{
+			name: "page content",
+			templates: map[string]string{
+				"root":         `{{ page_content }}`,
+				"page_content": `{{ .Databases }}`,
+			},
+			dataKeys:    []string{"Databases"},
+			hasTextData: false,
+		},
+		{
+			name:        "index with literal key",
+			templates:   map[string]string{"root": `{{ index . "Databases" }}`},
+			dataKeys:    []string{"Databases"},
+			hasTextData: false,
+		},
+		{
+			name:        "index with computed key",
+			templates:   map[string]string{"root": `{{ $key := printf "%s" "Pages" }}{{ index . $key }}`},
+			dataKeys:    templateDataKeys,
+			hasTextData: true,
+		},
+		{
+			name:        "range over data",
+			templates:   map[string]string{"root": `{{ range $key, $value := . }}{{ $key }}{{ end }}`},
+			dataKeys:    templateDataKeys,
+			hasTextData: true,
+		},
+		{
+			name:        "data in a function",
+			templates:   map[string]string{"root": `{{ printf "%v" $ }}`},
+			dataKeys:    templateDataKeys,
+			hasTextData: true,
+		},
+		{
+			name:        "computed include",
+			templates:   map[string]string{"root": `{{ include .Page.Partial . }}`},
+			dataKeys:    templateDataKeys,
+			hasTextData: true,
+		},
+		{
+			name:        "toc",
+			templates:   map[string]string{"root": `{{ toc .Page.Headings }}`},
+			dataKeys:    nil,
+			hasTextData: true,
+		},
+		{
+			name: "recursive template",
+			templates: map[string]string{
+				"root": `{{ template "tree" . }}`,
+				"tree": `{{ .Databases }}{{ template "tree" (dict "parent" .) }}`,
+			},
+			dataKeys:    templateDataKeys,
+			hasTextData: true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			usage := usedTemplateData(parseTestTemplates(t, testCase.templates), []string{"root"})
+
+			require.Equal(t, testCase.dataKeys, usage.dataKeys)
+			require.Equal(t, testCase.hasTextData, usage.hasTextData)
+		})
+	}
+}
@@ -2,7 +2,10 @@ package stagen
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"text/template"
 
@@ -12,9 +15,19 @@ import (
 	"github.com/stagens/stagen/pkg/html_preprocessor"
 	"github.com/stagens/stagen/pkg/html_tokenizer"
 	"github.com/stagens/stagen/pkg/markdown"
+	"github.com/stagens/stagen/pkg/source_error"
 	"github.com/stagens/stagen/pkg/template_engine"
 )
 
+type ThemeRenderResult struct {
+	Content []byte
+	// PageContent is the content as the layout gets it, set by RenderContent when the page can reuse it
+	PageContent    string
+	HasPageContent bool
+	Dependencies   []string
+	DataKeys       []string
+}
+
 type Theme interface {
 	Name() string
 
@@ -22,14 +35,43 @@ type Theme interface {
 
 	Config() ThemeConfig
 
+	LoadTemplate(ctx context.Context, loadType template_engine.LoadType, name string) (*template_engine.TemplateFile, error)
+
 	Render(
 		ctx context.Context,
 		imports map[string][]SiteConfigTemplateImport,
 		layout string,
-		content []byte,
+		content template_engine.Source,
+		isMarkdown bool,
+		data map[string]any,
+		contentResult *ThemeRenderResult,
+	) (*ThemeRenderResult, error)
+
+	RenderContent(
+		ctx context.Context,
+		imports map[string][]SiteConfigTemplateImport,
+		content template_engine.Source,
 		isMarkdown bool,
 		data map[string]any,
-	) ([]byte, error)
+	) (*ThemeRenderResult, error)
+}
+
+type themeTemplate struct {
+	engine    template_engine.TemplateEngine
+	content   template_engine.Source
+	includes  []string
+	hasBlocks bool
+}
+
+// dataUsage is what the templates executed with the template data read from it
+func (t *themeTemplate) dataUsage(templatesNames ...string) templateDataUsage {
+	names := slices.Concat([]string{"page_content"}, templatesNames, t.includes)
+
+	return usedTemplateData(t.engine.ParseTrees(), names)
+}
+
+func sortedUnique(values ...[]string) []string {
+	return slices.Compact(slices.Sorted(slices.Values(slices.Concat(values...))))
 }
 
 type ThemeImpl struct {
@@ -90,7 +132,7 @@ func NewTheme(
 	addClosingTags := []string{"no"}
 
 	withoutClosingTags := make([]string, 0, len(html_tokenizer.WithoutClosingTags)+len(addClosingTags))
-	copy(withoutClosingTags, html_tokenizer.WithoutClosingTags)
+	withoutClosingTags = append(withoutClosingTags, html_tokenizer.WithoutClosingTags...)
 	withoutClosingTags = append(withoutClosingTags, addClosingTags...)
 
 	return &ThemeImpl{
@@ -120,30 +162,199 @@ func (t *ThemeImpl) Config() ThemeConfig {
 	return t.config
 }
 
+func (t *ThemeImpl) LoadTemplate(
+	ctx context.Context,
+	loadType template_engine.LoadType,
+	name string,
+) (*template_engine.TemplateFile, error) {
+	return t.loader.LoadFile(ctx, loadType, name)
+}
+
+// Render renders the page in the layout, the page content of the content result is reused when it has one
 func (t *ThemeImpl) Render(
 	ctx context.Context,
 	imports map[string][]SiteConfigTemplateImport,
 	layout string,
-	content []byte,
+	content template_engine.Source,
+	isMarkdown bool,
+	data map[string]any,
+	contentResult *ThemeRenderResult,
+) (*ThemeRenderResult, error) {
+	renderResult, err := t.render(ctx, imports, layout, content, isMarkdown, data, contentResult)
+	if err != nil {
+		return nil, withSourceExcerpt(err, content)
+	}
+
+	return renderResult, nil
+}
+
+// RenderContent renders the page content alone, without the layout
+func (t *ThemeImpl) RenderContent(
+	ctx context.Context,
+	imports map[string][]SiteConfigTemplateImport,
+	content template_engine.Source,
+	isMarkdown bool,
+	data map[string]any,
+) (*ThemeRenderResult, error) {
+	renderResult, err := t.renderContent(ctx, imports, content, isMarkdown, data)
+	if err != nil {
+		return nil, withSourceExcerpt(err, content)
+	}
+
+	return renderResult, nil
+}
+
+func (t *ThemeImpl) render(
+	ctx context.Context,
+	imports map[string][]SiteConfigTemplateImport,
+	layout string,
+	content template_engine.Source,
+	isMarkdown bool,
+	data map[string]any,
+	contentResult *ThemeRenderResult,
+) (*ThemeRenderResult, error) {
+	var pageContent *string
+
+	if contentResult != nil && contentResult.HasPageContent {
+		pageContent = &contentResult.PageContent
+	}
+
+	pageTemplate, err := t.prepare(ctx, imports, content, isMarkdown, data, pageContent)
+	if err != nil {
+		return nil, err
+	}
+
+	templateEngine := pageTemplate.engine
+
+	templateResult, err := templateEngine.Execute(ctx, layout, pageTemplate.content, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render layout: %w", err)
+	}
+
+	templateResult, err = t.htmlPreprocessor.Postprocess(ctx, templateResult)
+	if err != nil {
+		return nil, fmt.Errorf("failed to postprocess layout: %w", err)
+	}
+
+	dependencies := slices.Sorted(maps.Keys(templateEngine.LoadedFiles()))
+	dataKeys := pageTemplate.dataUsage(t.name, layout).dataKeys
+
+	// the reused content did not run here, so its templates and data are taken from its render
+	if pageContent != nil {
+		dependencies = sortedUnique(dependencies, contentResult.Dependencies)
+		dataKeys = sortedUnique(dataKeys, contentResult.DataKeys)
+	}
+
+	renderResult := &ThemeRenderResult{
+		Content:        templateResult,
+		PageContent:    "",
+		HasPageContent: false,
+		Dependencies:   dependencies,
+		DataKeys:       dataKeys,
+	}
+
+	return renderResult, nil
+}
+
+func (t *ThemeImpl) renderContent(
+	ctx context.Context,
+	imports map[string][]SiteConfigTemplateImport,
+	content template_engine.Source,
 	isMarkdown bool,
 	data map[string]any,
-) ([]byte, error) {
+) (*ThemeRenderResult, error) {
+	pageTemplate, err := t.prepare(ctx, imports, content, isMarkdown, data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	templateResult, err := pageTemplate.engine.Execute(ctx, "", pageTemplate.content, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render content: %w", err)
+	}
+
+	pageContent := ""
+
+	if !pageTemplate.hasBlocks {
+		pageContent, err = t.renderPageContent(ctx, pageTemplate.engine, isMarkdown)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render page content: %w", err)
+		}
+
+		templateResult = []byte(pageContent)
+	} else if isMarkdown {
+		templateResult, err = t.markdown.Render(templateResult)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render markdown: %w", err)
+		}
+	}
+
+	templateResult, err = t.htmlPreprocessor.Postprocess(ctx, templateResult)
+	if err != nil {
+		return nil, fmt.Errorf("failed to postprocess content: %w", err)
+	}
+
+	dataUsage := pageTemplate.dataUsage(t.name)
+
+	// blocks are filled by the layout and the texts are not there yet, the content is rendered again then
+	hasPageContent := !pageTemplate.hasBlocks && !dataUsage.hasTextData
+
+	renderResult := &ThemeRenderResult{
+		Content:        templateResult,
+		PageContent:    pageContent,
+		HasPageContent: hasPageContent,
+		Dependencies:   slices.Sorted(maps.Keys(pageTemplate.engine.LoadedFiles())),
+		DataKeys:       dataUsage.dataKeys,
+	}
+
+	return renderResult, nil
+}
+
+func (t *ThemeImpl) prepare(
+	ctx context.Context,
+	imports map[string][]SiteConfigTemplateImport,
+	content template_engine.Source,
+	isMarkdown bool,
+	data map[string]any,
+	pageContent *string,
+) (*themeTemplate, error) {
 	var templateEngine template_engine.TemplateEngine
 
+	pageTemplate := &themeTemplate{
+		engine:    nil,
+		content:   content,
+		includes:  make([]string, 0),
+		hasBlocks: false,
+	}
+
 	templateEngine = template_engine.NewWithExtraTemplateFunctions(
 		t.name,
 		template_engine.TemplateFormatText,
 		t.loader,
 		template.FuncMap{
 			"page_content": func() (string, error) {
+				if pageContent != nil {
+					return *pageContent, nil
+				}
+
 				return t.renderPageContent(ctx, templateEngine, isMarkdown)
 			},
 			"markdown": func(text string) (string, error) {
 				return t.renderMarkdown(ctx, text)
 			},
 			"includes": func(includes []SiteConfigTemplateInclude) (string, error) {
+				// the includes are named by the config, so they are walked for the data by the names rendered
+				for _, includeValue := range includes {
+					pageTemplate.includes = append(pageTemplate.includes, includeValue.Name())
+				}
+
 				return t.includes(ctx, templateEngine, data, includes)
 			},
+			"toc":      renderToc,
+			"hreflang": renderHreflang,
+			"T": func(key string, args ...any) string {
+				return translate(data, key, args...)
+			},
 		},
 	)
 
@@ -158,37 +369,45 @@ func (t *ThemeImpl) Render(
 		}
 	}
 
-	var extras []byte
-
-	extras, content, err := t.htmlPreprocessor.Preprocess(ctx, content)
+	preprocessResult, err := t.htmlPreprocessor.Preprocess(ctx, []byte(content.Content))
 	if err != nil {
 		return nil, fmt.Errorf("failed to preprocess content: %w", err)
 	}
 
-	contentStr := string(content)
+	contentSource := template_engine.Source{
+		File:    content.File,
+		Line:    content.Line,
+		Content: string(preprocessResult.Content),
+	}
 
-	hasBlocks, err := templateEngine.HasBlocks(ctx, contentStr)
+	hasBlocks, err := templateEngine.HasBlocks(ctx, contentSource)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check block: %w", err)
 	}
 
+	// the define goes on its own line before the content, so the positions in the content are kept
 	if !hasBlocks {
-		contentStr = `{{- define "page_content" -}}` + contentStr + `{{- end -}}`
+		contentSource.Content = `{{- define "page_content" -}}` + "\n" + contentSource.Content + `{{- end -}}`
+		contentSource.Line--
 	}
 
-	contentStr = string(extras) + contentStr
+	for _, extra := range preprocessResult.Extras {
+		extraSource := template_engine.Source{
+			File:    content.File,
+			Line:    content.Line + extra.Line - 1,
+			Content: string(extra.Content),
+		}
 
-	templateResult, err := templateEngine.Execute(ctx, layout, contentStr, data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to render layout: %w", err)
+		if err = templateEngine.Define(ctx, extraSource); err != nil {
+			return nil, fmt.Errorf("failed to define macro '%s': %w", extra.Name, err)
+		}
 	}
 
-	templateResult, err = t.htmlPreprocessor.Postprocess(ctx, templateResult)
-	if err != nil {
-		return nil, fmt.Errorf("failed to postprocess layout: %w", err)
-	}
+	pageTemplate.engine = templateEngine
+	pageTemplate.content = contentSource
+	pageTemplate.hasBlocks = hasBlocks
 
-	return templateResult, err
+	return pageTemplate, nil
 }
 
 func (t *ThemeImpl) renderPageContent(
@@ -207,10 +426,10 @@ func (t *ThemeImpl) renderPageContent(
 			return "", fmt.Errorf("failed to render markdown: %w", err)
 		}
 
-		return string(markdownResult), nil
+		renderResult = markdownResult
 	}
 
-	return string(renderResult), err
+	return headingsWithIds(string(renderResult)), nil
 }
 
 func (t *ThemeImpl) renderMarkdown(
@@ -244,3 +463,18 @@ func (t *ThemeImpl) includes(
 
 	return string(results), nil
 }
+
+// withSourceExcerpt replaces the excerpt of errors in the page content,
+// so it shows the page as written instead of the preprocessed template
+func withSourceExcerpt(err error, content template_engine.Source) error {
+	var sourceErr *source_error.SourceError
+	if !errors.As(err, &sourceErr) || sourceErr.File != content.File {
+		return err
+	}
+
+	if excerpt := source_error.Excerpt(content.Content, content.Line, sourceErr.Line, sourceErr.Column); excerpt != "" {
+		sourceErr.Excerpt = excerpt
+	}
+
+	return err
+}
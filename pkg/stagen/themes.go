@@ -99,7 +99,7 @@ func (s *Impl) addTheme(
 	importPaths = append(importPaths, filepath.Join(templatesDir, "imports"))
 	includePaths = append(includePaths, filepath.Join(templatesDir, "includes"))
 
-	for _, extension := range s.extensions {
+	for _, extension := range s.sortedExtensions() {
 		layoutsIncludePaths = append(layoutsIncludePaths, filepath.Join(extension.Path(), "layouts"))
 		importPaths = append(importPaths, filepath.Join(extension.Path(), "imports"))
 		includePaths = append(includePaths, filepath.Join(extension.Path(), "includes"))
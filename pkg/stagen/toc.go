@@ -0,0 +1,231 @@
+package stagen
+
+import (
+	"html"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	netHtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+type TocHeading struct {
+	Level int
+	Text  string
+	Id    string
+}
+
+var headingsLevels = map[atom.Atom]int{
+	atom.H1: 1,
+	atom.H2: 2,
+	atom.H3: 3,
+	atom.H4: 4,
+	atom.H5: 5,
+	atom.H6: 6,
+}
+
+// tocHeadings extracts the headings of the rendered page content within the levels
+func tocHeadings(content string, minLevel int, maxLevel int) []TocHeading {
+	headings := make([]TocHeading, 0)
+
+	tokenizer := netHtml.NewTokenizer(strings.NewReader(content))
+
+	var (
+		heading *TocHeading
+		text    strings.Builder
+	)
+
+	for {
+		switch tokenizer.Next() {
+		case netHtml.ErrorToken:
+			return headings
+
+		case netHtml.StartTagToken:
+			token := tokenizer.Token()
+
+			level, ok := headingsLevels[token.DataAtom]
+			if !ok || heading != nil || level < minLevel || level > maxLevel {
+				continue
+			}
+
+			heading = &TocHeading{
+				Level: level,
+				Text:  "",
+				Id:    "",
+			}
+
+			for _, attr := range token.Attr {
+				if attr.Key == "id" {
+					heading.Id = attr.Val
+				}
+			}
+
+			text.Reset()
+
+		case netHtml.TextToken:
+			if heading != nil {
+				text.Write(tokenizer.Text())
+			}
+
+		case netHtml.EndTagToken:
+			token := tokenizer.Token()
+
+			if heading == nil || headingsLevels[token.DataAtom] != heading.Level {
+				continue
+			}
+
+			heading.Text = strings.Join(strings.Fields(text.String()), " ")
+
+			headings = append(headings, *heading)
+
+			heading = nil
+
+		default:
+		}
+	}
+}
+
+type missingHeadingId struct {
+	offset int
+	text   string
+}
+
+// headingsWithIds gives the headings without an id one generated from their text the way markdown does,
+// so the toc of html pages links to them too
+func headingsWithIds(content string) string {
+	ids := parser.NewContext().IDs()
+
+	missingIds := make([]missingHeadingId, 0)
+
+	tokenizer := netHtml.NewTokenizer(strings.NewReader(content))
+
+	var (
+		offset  int
+		heading *missingHeadingId
+		level   int
+		text    strings.Builder
+	)
+
+	for tokenType := tokenizer.Next(); tokenType != netHtml.ErrorToken; tokenType = tokenizer.Next() {
+		raw := string(tokenizer.Raw())
+		start := offset
+		offset += len(raw)
+
+		switch tokenType {
+		case netHtml.StartTagToken, netHtml.SelfClosingTagToken:
+			token := tokenizer.Token()
+
+			hasId := false
+
+			for _, attr := range token.Attr {
+				if attr.Key == "id" {
+					hasId = true
+
+					ids.Put([]byte(attr.Val))
+				}
+			}
+
+			tokenLevel, ok := headingsLevels[token.DataAtom]
+			if !ok || hasId || heading != nil || tokenType == netHtml.SelfClosingTagToken {
+				continue
+			}
+
+			heading = &missingHeadingId{
+				offset: start + len(raw) - len(">"),
+				text:   "",
+			}
+			level = tokenLevel
+
+			text.Reset()
+
+		case netHtml.TextToken:
+			if heading != nil {
+				text.Write(tokenizer.Text())
+			}
+
+		case netHtml.EndTagToken:
+			if heading == nil || headingsLevels[tokenizer.Token().DataAtom] != level {
+				continue
+			}
+
+			heading.text = strings.Join(strings.Fields(text.String()), " ")
+
+			missingIds = append(missingIds, *heading)
+
+			heading = nil
+
+		default:
+		}
+	}
+
+	if len(missingIds) == 0 {
+		return content
+	}
+
+	result := strings.Builder{}
+
+	last := 0
+
+	for _, missingId := range missingIds {
+		id := ids.Generate([]byte(missingId.text), ast.KindHeading)
+
+		result.WriteString(content[last:missingId.offset])
+		result.WriteString(` id="` + string(id) + `"`)
+
+		last = missingId.offset
+	}
+
+	result.WriteString(content[last:])
+
+	return result.String()
+}
+
+// renderToc renders the headings as nested lists, deeper headings are nested in the previous one
+func renderToc(headings []TocHeading) string {
+	if len(headings) == 0 {
+		return ""
+	}
+
+	result := strings.Builder{}
+
+	levels := make([]int, 0)
+
+	for _, heading := range headings {
+		switch {
+		case len(levels) == 0 || heading.Level > levels[len(levels)-1]:
+			result.WriteString("<ul>")
+
+			levels = append(levels, heading.Level)
+
+		default:
+			result.WriteString("</li>")
+
+			for len(levels) > 1 && heading.Level <= levels[len(levels)-2] {
+				result.WriteString("</ul></li>")
+
+				levels = levels[:len(levels)-1]
+			}
+
+			levels[len(levels)-1] = heading.Level
+		}
+
+		result.WriteString("<li>")
+
+		if heading.Id != "" {
+			result.WriteString(`<a href="#` + html.EscapeString(heading.Id) + `">` + html.EscapeString(heading.Text) + "</a>")
+		} else {
+			result.WriteString(html.EscapeString(heading.Text))
+		}
+	}
+
+	result.WriteString("</li>")
+
+	for range levels[1:] {
+		result.WriteString("</ul></li>")
+	}
+
+	result.WriteString("</ul>")
+
+	return result.String()
+}
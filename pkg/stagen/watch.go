@@ -2,12 +2,31 @@ package stagen
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"maps"
 	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/pixality-inc/golang-core/storage"
+	"github.com/pixality-inc/golang-core/timetrack"
+	"github.com/pixality-inc/golang-core/util"
+
+	"github.com/stagens/stagen/pkg/source_error"
+)
+
+const watcherRebuildDelay = 100 * time.Millisecond
+
+var (
+	templateLoadDirs = []string{"layouts", "imports", "includes"}
+	modelDirs        = []string{"pages", "databases", "templates", "themes", "ext"}
 )
 
 func (s *Impl) Watch(ctx context.Context) error {
@@ -31,6 +50,17 @@ func (s *Impl) Watch(ctx context.Context) error {
 	return nil
 }
 
+// Rebuild rebuilds the outputs affected by the changed files as the watcher does, the names are relative to the work dir
+func (s *Impl) Rebuild(ctx context.Context, changedFiles []string) error {
+	filenames := make([]string, 0, len(changedFiles))
+
+	for _, changedFile := range changedFiles {
+		filenames = append(filenames, filepath.Join(s.workDir, changedFile))
+	}
+
+	return s.rebuild(ctx, filenames)
+}
+
 func (s *Impl) initWatcher(ctx context.Context) (*fsnotify.Watcher, error) {
 	log := s.log.GetLogger(ctx)
 
@@ -42,6 +72,14 @@ func (s *Impl) initWatcher(ctx context.Context) (*fsnotify.Watcher, error) {
 		return nil, fmt.Errorf("create new watcher: %w", err)
 	}
 
+	watchErrs := make([]error, 0)
+
+	watchFailed := func(err error) {
+		log.WithError(err).Errorf("Failed to watch")
+
+		watchErrs = append(watchErrs, err)
+	}
+
 	var addDir func(path string)
 
 	addDir = func(dir string) {
@@ -62,25 +100,25 @@ func (s *Impl) initWatcher(ctx context.Context) (*fsnotify.Watcher, error) {
 		// @todo!!!!
 		localStorage, ok := s.storage.(storage.LocalStorage)
 		if !ok {
-			log.WithError(ErrStorageIsNotALocalStorage).Errorf("Storage is not a local storage")
+			watchFailed(ErrStorageIsNotALocalStorage)
 
 			return
 		}
 
 		localDir, err := localStorage.LocalPath(ctx, dir)
 		if err != nil {
-			log.WithError(err).Errorf("Failed to get local directory: %s", dir)
+			watchFailed(fmt.Errorf("failed to get local directory %s: %w", dir, err))
 
 			return
 		}
 
 		if err = watcher.Add(localDir); err != nil {
-			log.WithError(err).Errorf("failed to watch directory %s", dir)
+			watchFailed(fmt.Errorf("failed to watch directory %s: %w", dir, err))
 		}
 
 		entries, err := s.storage.ReadDir(ctx, dir)
 		if err != nil {
-			log.WithError(err).Errorf("failed to read directory %s", dir)
+			watchFailed(fmt.Errorf("failed to read directory %s: %w", dir, err))
 
 			return
 		}
@@ -94,6 +132,14 @@ func (s *Impl) initWatcher(ctx context.Context) (*fsnotify.Watcher, error) {
 
 	addDir(sourceDir)
 
+	if len(watchErrs) > 0 && s.config.Settings().Strict() {
+		if wErr := watcher.Close(); wErr != nil {
+			log.WithError(wErr).Errorf("failed to close watcher")
+		}
+
+		return nil, fmt.Errorf("%w: %w", ErrStrict, errors.Join(watchErrs...))
+	}
+
 	return watcher, nil
 }
 
@@ -101,6 +147,15 @@ func (s *Impl) watcherWatch(ctx context.Context, watcher *fsnotify.Watcher) {
 	log := s.log.GetLogger(ctx)
 
 	buildDir := s.buildDir()
+	stagenDir := s.stagenDir()
+	buildSiblings := []string{s.siblingBuildDir(buildLinkSuffix), s.siblingBuildDir(buildOldSuffix)}
+	for _, buildVersion := range buildVersions {
+		buildSiblings = append(buildSiblings, s.siblingBuildDir(buildVersion))
+	}
+
+	changedFiles := make(map[string]struct{})
+
+	var rebuildTimer <-chan time.Time
 
 	for {
 		select {
@@ -119,17 +174,41 @@ func (s *Impl) watcherWatch(ctx context.Context, watcher *fsnotify.Watcher) {
 				event.Has(fsnotify.Create)
 
 			if doIt {
-				if path.Clean(event.Name) == buildDir || strings.HasSuffix(path.Clean(event.Name), buildDir) { // @todo!!!!
+				filename, err := s.watcherFilename(ctx, event.Name)
+				if err != nil {
+					log.WithError(err).Errorf("failed to resolve changed file %s", event.Name)
+
+					continue
+				}
+
+				if isPathInDir(filename, buildDir) ||
+					isPathInDir(filename, stagenDir) ||
+					slices.ContainsFunc(buildSiblings, func(dir string) bool { return isPathInDir(filename, dir) }) {
 					continue
 				}
 
-				log.Infof("Rebuild becase of %s of %s", event.Op, event.Name)
+				log.Infof("Rebuild becase of %s of %s", event.Op, filename)
+
+				changedFiles[filename] = struct{}{}
+
+				if rebuildTimer == nil {
+					rebuildTimer = time.After(watcherRebuildDelay)
+				}
+			}
+
+		case <-rebuildTimer:
+			rebuildTimer = nil
 
-				if err := s.rebuild(ctx); err != nil {
-					log.WithError(err).Errorf("failed to build after fs notify")
+			if err := s.rebuild(ctx, slices.Sorted(maps.Keys(changedFiles))); err != nil {
+				log.WithError(err).Errorf("failed to build after fs notify")
+
+				if excerpt := source_error.ExcerptOf(err); excerpt != "" {
+					log.Error("\n" + excerpt)
 				}
 			}
 
+			changedFiles = make(map[string]struct{})
+
 		case err, ok := <-watcher.Errors:
 			if !ok {
 				return
@@ -140,25 +219,451 @@ func (s *Impl) watcherWatch(ctx context.Context, watcher *fsnotify.Watcher) {
 	}
 }
 
-func (s *Impl) rebuild(ctx context.Context) error {
+func (s *Impl) watcherFilename(ctx context.Context, eventFilename string) (string, error) {
+	// @todo!!!!
+	localStorage, ok := s.storage.(storage.LocalStorage)
+	if !ok {
+		return "", ErrStorageIsNotALocalStorage
+	}
+
+	localWorkDir, err := localStorage.LocalPath(ctx, s.workDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to get local work directory: %w", err)
+	}
+
+	filename, err := filepath.Rel(localWorkDir, eventFilename)
+	if err != nil {
+		return "", fmt.Errorf("failed to get relative path of %s: %w", eventFilename, err)
+	}
+
+	return filepath.Join(s.workDir, filename), nil
+}
+
+func (s *Impl) rebuild(ctx context.Context, changedFiles []string) error {
 	s.rebuildMutex.Lock()
 	defer s.rebuildMutex.Unlock()
 
-	buildDir := s.buildDir()
+	log := s.log.GetLogger(ctx)
+
+	if !s.initialized {
+		return s.fullRebuild(ctx)
+	}
+
+	changes := s.classifyChanges(changedFiles)
 
-	if err := s.storage.DeleteDir(ctx, buildDir); err != nil {
-		return fmt.Errorf("failed to remove build directory: %w", err)
+	if changes.full {
+		return s.fullRebuild(ctx)
 	}
 
+	track := timetrack.New(ctx)
+
+	s.previousBuildManifest = s.buildManifest.Clone()
+
+	var pages []Page
+
+	err := s.inStaging(ctx, func() error {
+		var err error
+
+		if pages, err = s.rebuildChanges(ctx, changes); err != nil {
+			return err
+		}
+
+		if err = s.linkUnchangedBuildFiles(ctx); err != nil {
+			return fmt.Errorf("failed to keep unchanged files: %w", err)
+		}
+
+		if err = s.carryOverForeignFiles(ctx); err != nil {
+			return fmt.Errorf("failed to keep foreign files: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		// the live build is untouched, so is its manifest
+		s.buildManifest = s.previousBuildManifest
+
+		return s.failBuild(ctx, track.Finish(), err)
+	}
+
+	if err = s.saveBuildManifest(ctx); err != nil {
+		return s.failBuild(ctx, track.Finish(), fmt.Errorf("failed to save build manifest: %w", err))
+	}
+
+	duration := track.Finish()
+
+	if s.config.Settings().BuildReport() != "" {
+		if err = s.saveBuildReport(ctx, duration, nil); err != nil {
+			return err
+		}
+	}
+
+	log.Infof(
+		"Rebuilt %d pages and %d public files in %s",
+		len(pages),
+		len(changes.publicFiles),
+		util.FormatDuration(duration),
+	)
+
+	return nil
+}
+
+// rebuildChanges renders the pages and copies the files affected by the changes, it returns the rendered pages
+func (s *Impl) rebuildChanges(ctx context.Context, changes *watcherChanges) ([]Page, error) {
+	pagesToRender := make(map[string]struct{})
+
+	addPages := func(pagesIds ...string) {
+		for _, pageId := range pagesIds {
+			pagesToRender[pageId] = struct{}{}
+		}
+	}
+
+	if len(changes.modelFiles) > 0 {
+		oldPages := s.pages
+		oldAliases := s.aliases
+		oldPagesTexts := s.pagesTexts
+		oldCopiedFiles := s.pagesCopiedFiles()
+		oldAggDictsData := s.aggDictsData
+		oldDatabases := s.databases
+		oldSections := s.sections
+		oldPaginators := s.paginators
+		oldPagesTranslations := s.pagesTranslations
+
+		oldPagesRelations := make(map[string]PageRelations, len(s.pages))
+		for pageId, page := range s.pages {
+			oldPagesRelations[pageId] = s.pageRelations(page)
+		}
+
+		oldReport := s.report
+
+		s.resetModel()
+
+		s.report.KeepOutput(oldReport)
+
+		if err := s.init(ctx); err != nil {
+			return nil, fmt.Errorf("failed to initialize: %w", err)
+		}
+
+		listingChanged := false
+
+		for pageId, page := range s.pages {
+			oldPage, ok := oldPages[pageId]
+
+			if !ok ||
+				pageFingerprint(oldPage, true) != pageFingerprint(page, true) ||
+				!pageRelationsEqual(oldPagesRelations[pageId], s.pageRelations(page)) ||
+				!paginatorsEqual(oldPaginators[pageId], s.paginators[pageId]) ||
+				!translationsEqual(oldPagesTranslations[pageId], s.pagesTranslations[pageId]) {
+				addPages(pageId)
+			}
+
+			if !ok || pageFingerprint(oldPage, false) != pageFingerprint(page, false) || textChanged(oldPagesTexts[pageId], s.pagesTexts[pageId]) {
+				listingChanged = true
+			}
+		}
+
+		for pageId, oldPage := range oldPages {
+			if _, ok := s.pages[pageId]; ok {
+				continue
+			}
+
+			listingChanged = true
+
+			if err := s.removeBuildPage(ctx, oldPage); err != nil {
+				return nil, fmt.Errorf("failed to remove page '%s': %w", pageId, err)
+			}
+		}
+
+		if listingChanged {
+			addPages(s.dependencies.Dependants(dataDependency("Pages"))...)
+		}
+
+		if !aggDictsDataEqual(oldAggDictsData, s.aggDictsData) {
+			addPages(s.dependencies.Dependants(dataDependency("AggDicts"))...)
+			addPages(s.dependencies.Dependants(dataDependency("AggDictsData"))...)
+		}
+
+		if !sectionsEqual(oldSections, s.sections) {
+			addPages(s.dependencies.Dependants(dataDependency("Sections"))...)
+		}
+
+		if !databasesEqual(oldDatabases, s.databases) {
+			addPages(s.dependencies.Dependants(dataDependency("Databases"))...)
+		}
+
+		if err := s.buildAliases(ctx); err != nil {
+			return nil, fmt.Errorf("failed to build aliases: %w", err)
+		}
+
+		for aliasFilename := range oldAliases {
+			if _, ok := s.aliases[aliasFilename]; ok {
+				continue
+			}
+
+			if err := s.removeBuildFile(ctx, aliasFilename); err != nil {
+				return nil, fmt.Errorf("failed to remove alias '%s': %w", aliasFilename, err)
+			}
+		}
+
+		if err := s.copyPagesFiles(ctx); err != nil {
+			return nil, fmt.Errorf("failed to copy pages files: %w", err)
+		}
+
+		copiedFiles := s.pagesCopiedFiles()
+
+		for filename := range oldCopiedFiles {
+			if _, ok := copiedFiles[filename]; ok {
+				continue
+			}
+
+			if err := s.removeBuildFile(ctx, filename); err != nil {
+				return nil, fmt.Errorf("failed to remove file '%s': %w", filename, err)
+			}
+		}
+	}
+
+	// the contents rendered for the texts may use the changed templates
+	if len(changes.templateFiles) > 0 {
+		s.pagesContents = make(map[string]*PageContent)
+	}
+
+	for _, templateFile := range changes.templateFiles {
+		dependants := s.dependencies.Dependants(templateFile)
+
+		exists, err := s.storage.FileExists(ctx, templateFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check if file %s exists: %w", templateFile, err)
+		}
+
+		if exists && len(dependants) > 0 {
+			addPages(dependants...)
+		} else {
+			// a new or removed template can change how other templates are resolved
+			addPages(slices.Collect(maps.Keys(s.pages))...)
+		}
+	}
+
+	pages := make([]Page, 0, len(pagesToRender))
+
+	for _, pageId := range slices.Sorted(maps.Keys(pagesToRender)) {
+		if page, ok := s.pages[pageId]; ok {
+			pages = append(pages, page)
+		}
+	}
+
+	if err := s.buildPages(ctx, pages); err != nil {
+		return nil, fmt.Errorf("failed to build: %w", err)
+	}
+
+	for _, publicFilename := range changes.publicFiles {
+		if err := s.updatePublicFile(ctx, publicFilename); err != nil {
+			return nil, fmt.Errorf("failed to update public file '%s': %w", publicFilename, err)
+		}
+	}
+
+	return pages, nil
+}
+
+func (s *Impl) fullRebuild(ctx context.Context) error {
+	s.resetModel()
+	s.createdDirs = make(map[string]struct{})
+	s.dependencies = NewDependencyGraph()
+
+	return s.Build(ctx)
+}
+
+func (s *Impl) resetModel() {
 	s.initialized = false
 	s.extensions = make(map[string]Extension)
 	s.databases = make(map[string]Database)
 	s.aggDicts = make(map[string]SiteAggDictConfig)
 	s.aggDictsData = make(map[string]map[string]map[string][]Page)
 	s.generators = make(map[string]Generator)
+	s.generatedPages = make(map[string]string)
 	s.pages = make(map[string]Page)
+	s.aliases = make(map[string]*PageAlias)
+	s.passthroughFiles = make(map[string]string)
+	s.pagesTexts = make(map[string]*PageText)
+	s.pagesContents = make(map[string]*PageContent)
+	s.resetPagesData()
+	s.sections = make(map[string]*Section)
+	s.pagesNavigation = make(map[string]*PageNavigation)
+	s.paginators = make(map[string]*Paginator)
+	s.languages = make([]*Language, 0)
+	s.pagesTranslations = make(map[string][]*PageTranslation)
+	s.i18n = make(map[string]map[string]string)
 	s.themes = make(map[string]Theme)
-	s.createdDirs = make(map[string]struct{})
+	s.report = NewBuildReport()
+}
 
-	return s.Build(ctx)
+type watcherChanges struct {
+	full          bool
+	modelFiles    []string
+	templateFiles []string
+	publicFiles   []string
+}
+
+func (s *Impl) classifyChanges(changedFiles []string) *watcherChanges {
+	changes := &watcherChanges{
+		full:          false,
+		modelFiles:    make([]string, 0),
+		templateFiles: make([]string, 0),
+		publicFiles:   make([]string, 0),
+	}
+
+	for _, changedFile := range changedFiles {
+		filename, err := filepath.Rel(filepath.Join(s.workDir, "."), changedFile)
+		if err != nil {
+			changes.full = true
+
+			continue
+		}
+
+		parts := strings.Split(filepath.ToSlash(filename), "/")
+
+		// themes/<theme>/... and ext/<extension>/... are laid out like the project root
+		componentParts := parts
+
+		if len(parts) > 2 && (parts[0] == "themes" || parts[0] == "ext") {
+			componentParts = parts[2:]
+		}
+
+		switch {
+		case len(componentParts) > 1 && componentParts[0] == "public":
+			changes.publicFiles = append(changes.publicFiles, filepath.Join(componentParts[1:]...))
+
+		case len(parts) > 2 && parts[0] == "templates" && slices.Contains(templateLoadDirs, parts[1]):
+			changes.templateFiles = append(changes.templateFiles, changedFile)
+
+		case len(parts) > 2 && (parts[0] == "themes" || parts[0] == "ext") && slices.Contains(templateLoadDirs, parts[2]):
+			changes.templateFiles = append(changes.templateFiles, changedFile)
+
+		case len(parts) > 1 && slices.Contains(modelDirs, parts[0]):
+			changes.modelFiles = append(changes.modelFiles, changedFile)
+
+		default:
+			changes.full = true
+		}
+	}
+
+	return changes
+}
+
+func (s *Impl) removeBuildPage(ctx context.Context, page Page) error {
+	s.dependencies.Remove(page.Id())
+
+	filename := s.pageBuildFilename(page.FileInfo())
+
+	s.log.GetLogger(ctx).Infof("Removing page '%s' output %s...", page.Id(), filename)
+
+	return s.removeBuildFile(ctx, filename)
+}
+
+func (s *Impl) updatePublicFile(ctx context.Context, publicFilename string) error {
+	dirs, err := s.publicDirs(ctx)
+	if err != nil {
+		return err
+	}
+
+	// later public dirs override earlier ones, so the last existing source wins
+	for _, dir := range slices.Backward(dirs) {
+		sourceFilename := filepath.Join(dir, publicFilename)
+
+		if exists, err := s.storage.FileExists(ctx, sourceFilename); err != nil {
+			return fmt.Errorf("failed to check if file %s exists: %w", sourceFilename, err)
+		} else if !exists {
+			continue
+		}
+
+		return s.copyPublicFile(ctx, sourceFilename, publicFilename)
+	}
+
+	return s.removeBuildFile(ctx, publicFilename)
+}
+
+// aggDictsDataEqual compares the agg dicts by the ids of their pages, the pages are in the build order
+func aggDictsDataEqual(a map[string]map[string]map[string][]Page, b map[string]map[string]map[string][]Page) bool {
+	return maps.EqualFunc(a, b, func(aAggDictData map[string]map[string][]Page, bAggDictData map[string]map[string][]Page) bool {
+		return maps.EqualFunc(aAggDictData, bAggDictData, func(aValues map[string][]Page, bValues map[string][]Page) bool {
+			return maps.EqualFunc(aValues, bValues, func(aPages []Page, bPages []Page) bool {
+				return slices.EqualFunc(aPages, bPages, func(aPage Page, bPage Page) bool {
+					return aPage.Id() == bPage.Id()
+				})
+			})
+		})
+	})
+}
+
+// databasesEqual compares the databases rows, they are decoded yaml values and are compared deeply
+func databasesEqual(a map[string]Database, b map[string]Database) bool {
+	return maps.EqualFunc(a, b, func(aDatabase Database, bDatabase Database) bool {
+		return reflect.DeepEqual(aDatabase.Data(), bDatabase.Data())
+	})
+}
+
+func pageFingerprint(page Page, withContent bool) string {
+	pageConfig := page.Config()
+
+	hash := sha256.New()
+
+	if withContent {
+		_, _ = hash.Write(page.Content()) //nolint:errcheck
+	}
+
+	_, _ = fmt.Fprint( //nolint:errcheck
+		hash,
+		page.Id(),
+		page.Name(),
+		page.Uri(),
+		page.FileInfo().Filename,
+		pageConfig.Theme(),
+		pageConfig.Layout(),
+		pageConfig.Title(),
+		pageConfig.IsHidden(),
+		pageConfig.IsDraft(),
+		pageConfig.IsSystem(),
+		pageConfig.Variables(),
+		templateNames(pageConfig.Imports()),
+		templateNames(pageConfig.Includes()),
+		extrasValues(pageConfig.Extras()),
+	)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func textChanged(oldPageText *PageText, pageText *PageText) bool {
+	if oldPageText == nil || pageText == nil {
+		return oldPageText != pageText
+	}
+
+	return oldPageText.Summary != pageText.Summary ||
+		oldPageText.Plain != pageText.Plain ||
+		!slices.Equal(oldPageText.Toc, pageText.Toc)
+}
+
+func templateNames[T interface{ Name() string }](templates map[string][]T) map[string][]string {
+	names := make(map[string][]string, len(templates))
+
+	for key, values := range templates {
+		for _, value := range values {
+			names[key] = append(names[key], value.Name())
+		}
+	}
+
+	return names
+}
+
+func extrasValues(extras map[string][]SiteConfigTemplateExtra) map[string][]string {
+	values := make(map[string][]string, len(extras))
+
+	for key, extrasList := range extras {
+		for _, extra := range extrasList {
+			values[key] = append(values[key], fmt.Sprint(extra.Url(), extra.Options()))
+		}
+	}
+
+	return values
+}
+
+func isPathInDir(filename string, dir string) bool {
+	return filename == dir || strings.HasPrefix(filename, dir+"/")
 }
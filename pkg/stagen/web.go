@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/gabriel-vasile/mimetype"
 	"github.com/pixality-inc/golang-core/http"
@@ -87,7 +88,8 @@ func (s *Impl) httpHandler(ctx *fasthttp.RequestCtx) {
 		path.Join(buildDir, uri, "/index.html"),
 	}
 
-	if !s.config.Settings().UseUriHtmlFileExtension() {
+	// pretty urls work without the fallback, like on any static host
+	if !s.config.Settings().UseUriHtmlFileExtension() && !s.config.Settings().PrettyUrls() {
 		filesToCheck = append(filesToCheck, path.Join(buildDir, uri+".html"))
 	}
 
@@ -103,7 +105,7 @@ func (s *Impl) httpHandler(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	for _, filename := range filesToCheck {
+	for index, filename := range filesToCheck {
 		// @todo!!!!
 		filePath, err := localStorage.LocalPath(ctx, filename)
 		if err != nil {
@@ -132,6 +134,16 @@ func (s *Impl) httpHandler(ctx *fasthttp.RequestCtx) {
 			continue
 		}
 
+		if index == 1 && s.config.Settings().PrettyUrls() && s.config.Settings().TrailingSlash() != TrailingSlashNever && !strings.HasSuffix(uri, "/") {
+			if fErr := file.Close(); fErr != nil {
+				s.log.GetLogger(ctx).WithError(fErr).Error("can't close file")
+			}
+
+			ctx.Redirect(uri+"/", fasthttp.StatusMovedPermanently)
+
+			return
+		}
+
 		s.httpHandleFile(ctx, filePath, file)
 
 		return
@@ -31,15 +31,24 @@ func NewFsLoader(
 }
 
 func (t *FsLoader) Load(ctx context.Context, loadType LoadType, path string) (string, error) {
+	templateFile, err := t.LoadFile(ctx, loadType, path)
+	if err != nil {
+		return "", err
+	}
+
+	return templateFile.Content, nil
+}
+
+func (t *FsLoader) LoadFile(ctx context.Context, loadType LoadType, path string) (*TemplateFile, error) {
 	includePaths, ok := t.includePaths[loadType]
 	if !ok {
-		return "", fmt.Errorf("%w: %s", ErrLoadTypeNotFound, loadType)
+		return nil, fmt.Errorf("%w: %s", ErrLoadTypeNotFound, loadType)
 	}
 
-	loadFile := func(filename string) (string, error) {
+	loadFile := func(filename string) (*TemplateFile, error) {
 		file, err := t.storage.ReadFile(ctx, filename)
 		if err != nil {
-			return "", fmt.Errorf("faile to open file %s: %w", filename, err)
+			return nil, fmt.Errorf("faile to open file %s: %w", filename, err)
 		}
 
 		defer func() {
@@ -50,10 +59,15 @@ func (t *FsLoader) Load(ctx context.Context, loadType LoadType, path string) (st
 
 		content, err := io.ReadAll(file)
 		if err != nil {
-			return "", fmt.Errorf("failed to read file %s: %w", filename, err)
+			return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+		}
+
+		templateFile := &TemplateFile{
+			Filename: filename,
+			Content:  string(content),
 		}
 
-		return string(content), nil
+		return templateFile, nil
 	}
 
 	for _, includePath := range includePaths {
@@ -61,7 +75,7 @@ func (t *FsLoader) Load(ctx context.Context, loadType LoadType, path string) (st
 			filename := filepath.Join(includePath, path+extension)
 
 			if exists, err := t.storage.FileExists(ctx, filename); err != nil {
-				return "", fmt.Errorf("faile to check if file %s exists: %w", filename, err)
+				return nil, fmt.Errorf("faile to check if file %s exists: %w", filename, err)
 			} else if !exists {
 				continue
 			}
@@ -70,5 +84,5 @@ func (t *FsLoader) Load(ctx context.Context, loadType LoadType, path string) (st
 		}
 	}
 
-	return "", fmt.Errorf("%w: %s", ErrTemplateNotFound, path)
+	return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, path)
 }
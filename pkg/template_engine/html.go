@@ -30,6 +30,23 @@ func (t *HtmlTemplate) Parse(content string) error {
 	return err
 }
 
+// ParseNamed parses content as an associated template, so the positions of its errors refer to name
+func (t *HtmlTemplate) ParseNamed(name string, content string) error {
+	tmpl, err := t.template.New(name).Parse(content)
+	if err != nil {
+		return err
+	}
+
+	// like Parse, a non-empty body becomes the body of the template
+	if tmpl.Tree != nil && (t.template.Tree == nil || !parse.IsEmptyTree(tmpl.Tree.Root)) {
+		if _, err = t.template.AddParseTree(t.template.Name(), tmpl.Tree); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (t *HtmlTemplate) Templates() []BasicTemplate {
 	return util.SliceOfRefsToInterfaces[template.Template, BasicTemplate](t.template.Templates())
 }
@@ -41,3 +58,16 @@ func (t *HtmlTemplate) Funcs(functions template.FuncMap) {
 func (t *HtmlTemplate) ParseTree() *parse.Tree {
 	return t.template.Tree
 }
+
+// ParseTrees are the parse trees of the associated templates by their names
+func (t *HtmlTemplate) ParseTrees() map[string]*parse.Tree {
+	trees := make(map[string]*parse.Tree)
+
+	for _, tmpl := range t.template.Templates() {
+		if tmpl.Tree != nil {
+			trees[tmpl.Name()] = tmpl.Tree
+		}
+	}
+
+	return trees
+}
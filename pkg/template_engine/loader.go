@@ -18,6 +18,12 @@ const (
 	LoadTypeInclude LoadType = "include"
 )
 
+type TemplateFile struct {
+	Filename string
+	Content  string
+}
+
 type Loader interface {
 	Load(ctx context.Context, loadType LoadType, path string) (string, error)
+	LoadFile(ctx context.Context, loadType LoadType, path string) (*TemplateFile, error)
 }
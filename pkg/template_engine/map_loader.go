@@ -15,15 +15,29 @@ func NewMapLoader(templates map[LoadType]map[string]string) *MapLoader {
 	}
 }
 
-func (t *MapLoader) Load(_ context.Context, loadType LoadType, path string) (string, error) {
+func (t *MapLoader) Load(ctx context.Context, loadType LoadType, path string) (string, error) {
+	templateFile, err := t.LoadFile(ctx, loadType, path)
+	if err != nil {
+		return "", err
+	}
+
+	return templateFile.Content, nil
+}
+
+func (t *MapLoader) LoadFile(_ context.Context, loadType LoadType, path string) (*TemplateFile, error) {
 	templates, ok := t.templates[loadType]
 	if !ok {
-		return "", fmt.Errorf("%w: %s", ErrLoadTypeNotFound, loadType)
+		return nil, fmt.Errorf("%w: %s", ErrLoadTypeNotFound, loadType)
 	}
 
 	if content, ok := templates[path]; ok {
-		return content, nil
+		templateFile := &TemplateFile{
+			Filename: string(loadType) + "/" + path,
+			Content:  content,
+		}
+
+		return templateFile, nil
 	}
 
-	return "", fmt.Errorf("%w: %s", ErrTemplateNotFound, path)
+	return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, path)
 }
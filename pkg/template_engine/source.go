@@ -0,0 +1,87 @@
+package template_engine
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+
+	"github.com/stagens/stagen/pkg/source_error"
+)
+
+var templateErrorRegexp = regexp.MustCompile(`(?s)^template: (.+?):(\d+):(?:(\d+):)? (.*)$`)
+
+type Source struct {
+	File    string
+	Line    int
+	Content string
+}
+
+func (e *Impl) addSource(name string, source Source) {
+	e.sourcesMutex.Lock()
+	defer e.sourcesMutex.Unlock()
+
+	e.sources[name] = source
+}
+
+func (e *Impl) source(name string) (Source, bool) {
+	e.sourcesMutex.Lock()
+	defer e.sourcesMutex.Unlock()
+
+	source, ok := e.sources[name]
+
+	return source, ok
+}
+
+func (e *Impl) sourceError(err error) error {
+	return newSourceError(err, e.source)
+}
+
+// newSourceError maps the position of a text/template error back to the source the template was parsed from
+func newSourceError(err error, lookup func(name string) (Source, bool)) error {
+	if err == nil {
+		return nil
+	}
+
+	// nested templates report the innermost position already
+	var sourceErr *source_error.SourceError
+	if errors.As(err, &sourceErr) {
+		return err
+	}
+
+	matches := templateErrorRegexp.FindStringSubmatch(err.Error())
+	if matches == nil {
+		return err
+	}
+
+	source, ok := lookup(matches[1])
+	if !ok {
+		return err
+	}
+
+	line, err2 := strconv.Atoi(matches[2])
+	if err2 != nil {
+		return err
+	}
+
+	column := 0
+
+	// text/template reports the byte offset in the line, columns start from 1
+	if matches[3] != "" {
+		if column, err2 = strconv.Atoi(matches[3]); err2 != nil {
+			return err
+		}
+
+		column++
+	}
+
+	sourceLine := max(0, source.Line)
+
+	return source_error.New(
+		source.File,
+		sourceLine+line-1,
+		column,
+		matches[4],
+		source_error.Excerpt(source.Content, sourceLine, sourceLine+line-1, column),
+		err,
+	)
+}
@@ -26,7 +26,9 @@ type Template interface {
 	BasicTemplate
 
 	Parse(content string) error
+	ParseNamed(name string, content string) error
 	Templates() []BasicTemplate
 	Funcs(functions template.FuncMap)
 	ParseTree() *parse.Tree
+	ParseTrees() map[string]*parse.Tree
 }
@@ -6,9 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"strconv"
 	"strings"
 	"sync"
 	textTemplate "text/template"
+	"text/template/parse"
 
 	"github.com/pixality-inc/golang-core/json"
 	"github.com/pixality-inc/golang-core/logger"
@@ -21,12 +23,15 @@ var (
 )
 
 type TemplateEngine interface {
-	HasBlocks(ctx context.Context, content string) (bool, error)
-	Execute(ctx context.Context, layout string, content string, data map[string]any) ([]byte, error)
+	HasBlocks(ctx context.Context, content Source) (bool, error)
+	Define(ctx context.Context, source Source) error
+	Execute(ctx context.Context, layout string, content Source, data map[string]any) ([]byte, error)
 	Render(ctx context.Context, name string) ([]byte, error)
 	RenderBlock(ctx context.Context, name string, data map[string]any) ([]byte, error)
 	Import(ctx context.Context, loadType LoadType, name string, withCache bool) ([]byte, error)
 	Include(ctx context.Context, name string, data map[string]any) ([]byte, error)
+	LoadedFiles() map[string]string
+	ParseTrees() map[string]*parse.Tree
 }
 
 type Impl struct {
@@ -39,7 +44,12 @@ type Impl struct {
 	context                context.Context // nolint:containedctx
 	data                   map[string]any
 	imported               map[string]struct{}
+	loadedFiles            map[string]string
+	sources                map[string]Source
+	defined                int
 	mutex                  sync.Mutex
+	executeMutex           sync.Mutex
+	sourcesMutex           sync.Mutex
 }
 
 func New(
@@ -73,7 +83,12 @@ func NewWithExtraTemplateFunctions(
 		context:                nil,
 		data:                   nil,
 		imported:               make(map[string]struct{}),
+		loadedFiles:            make(map[string]string),
+		sources:                make(map[string]Source),
+		defined:                0,
 		mutex:                  sync.Mutex{},
+		executeMutex:           sync.Mutex{},
+		sourcesMutex:           sync.Mutex{},
 	}
 
 	impl.addFuncs(tmpl)
@@ -81,11 +96,15 @@ func NewWithExtraTemplateFunctions(
 	return impl
 }
 
-func (e *Impl) HasBlocks(ctx context.Context, content string) (bool, error) {
+func (e *Impl) HasBlocks(ctx context.Context, content Source) (bool, error) {
 	tmpl := newTemplate(e.format, e.name)
 	e.addFuncs(tmpl)
 
-	if err := tmpl.Parse(content); err != nil {
+	if err := tmpl.Parse(content.Content); err != nil {
+		err = newSourceError(err, func(string) (Source, bool) {
+			return content, true
+		})
+
 		return false, fmt.Errorf("parse template content: %w", err)
 	}
 
@@ -96,7 +115,30 @@ func (e *Impl) HasBlocks(ctx context.Context, content string) (bool, error) {
 	return !noBlocks, nil
 }
 
-func (e *Impl) Execute(ctx context.Context, layout string, content string, data map[string]any) ([]byte, error) {
+func (e *Impl) Define(ctx context.Context, source Source) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.defined++
+
+	// no ':<number>' in the name, it would be taken for the position in the error messages
+	name := source.File + "::define#" + strconv.Itoa(e.defined)
+
+	e.log.GetLogger(ctx).Tracef("Define templates from '%s'", name)
+
+	e.addSource(name, source)
+
+	if err := e.template.ParseNamed(name, source.Content); err != nil {
+		return fmt.Errorf("parse templates from '%s': %w", source.File, e.sourceError(err))
+	}
+
+	return nil
+}
+
+func (e *Impl) Execute(ctx context.Context, layout string, content Source, data map[string]any) ([]byte, error) {
+	e.executeMutex.Lock()
+	defer e.executeMutex.Unlock()
+
 	e.context = ctx
 	e.addFuncs(e.template)
 
@@ -115,12 +157,14 @@ func (e *Impl) Execute(ctx context.Context, layout string, content string, data
 		writer.Write(importResult)
 	}
 
-	if err := tmpl.Parse(content); err != nil {
-		return nil, fmt.Errorf("parse template: %w", err)
+	e.addSource(e.name, content)
+
+	if err := tmpl.Parse(content.Content); err != nil {
+		return nil, fmt.Errorf("parse template: %w", e.sourceError(err))
 	}
 
 	if err := tmpl.Execute(writer, e.data); err != nil {
-		return nil, fmt.Errorf("execute template: %w", err)
+		return nil, fmt.Errorf("execute template: %w", e.sourceError(err))
 	}
 
 	if layout != "" {
@@ -141,7 +185,7 @@ func (e *Impl) Render(_ context.Context, name string) ([]byte, error) {
 	writer := bytes.NewBuffer(nil)
 
 	if err := tmpl.ExecuteTemplate(writer, name, e.data); err != nil {
-		return nil, fmt.Errorf("render template '%s': %w", name, err)
+		return nil, fmt.Errorf("render template '%s': %w", name, e.sourceError(err))
 	}
 
 	return writer.Bytes(), nil
@@ -163,7 +207,7 @@ func (e *Impl) RenderBlock(_ context.Context, name string, data map[string]any)
 	}
 
 	if err := tmpl.ExecuteTemplate(writer, name, data); err != nil {
-		return nil, fmt.Errorf("render block '%s': %w", name, err)
+		return nil, fmt.Errorf("render block '%s': %w", name, e.sourceError(err))
 	}
 
 	return writer.Bytes(), nil
@@ -183,13 +227,21 @@ func (e *Impl) Import(ctx context.Context, loadType LoadType, name string, withC
 
 	e.log.GetLogger(ctx).Tracef("Import template type %s '%s'", loadType, name)
 
-	content, err := e.loader.Load(ctx, loadType, name)
+	templateFile, err := e.loader.LoadFile(ctx, loadType, name)
 	if err != nil {
 		return nil, fmt.Errorf("import template type %s '%s': %w", loadType, name, err)
 	}
 
-	if err = e.template.Parse(content); err != nil {
-		return nil, fmt.Errorf("parse template type %s '%s': %w", loadType, name, err)
+	e.loadedFiles[templateFile.Filename] = templateFile.Content
+
+	e.addSource(templateFile.Filename, Source{
+		File:    templateFile.Filename,
+		Line:    1,
+		Content: templateFile.Content,
+	})
+
+	if err = e.template.ParseNamed(templateFile.Filename, templateFile.Content); err != nil {
+		return nil, fmt.Errorf("parse template type %s '%s': %w", loadType, name, e.sourceError(err))
 	}
 
 	if withCache {
@@ -217,6 +269,20 @@ func (e *Impl) Include(ctx context.Context, name string, data map[string]any) ([
 	return result, nil
 }
 
+func (e *Impl) LoadedFiles() map[string]string {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return maps.Clone(e.loadedFiles)
+}
+
+func (e *Impl) ParseTrees() map[string]*parse.Tree {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.template.ParseTrees()
+}
+
 func (e *Impl) addFuncs(tmpl Template) {
 	tmpl.Funcs(textTemplate.FuncMap{
 		"default": func(value any, defaultValue any) any {
@@ -0,0 +1 @@
+const tpl = `{{ name }}`;